@@ -0,0 +1,103 @@
+package ibt
+
+import (
+	"fmt"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/utilities"
+)
+
+// AccessorValueType is an interface containing all possible types read by an Accessor
+type AccessorValueType interface {
+	uint8 | bool | int | uint32 | float32 | float64
+}
+
+// Accessor is a compiled handle for reading a single telemetry variable from the current raw tick of a Parser.
+//
+// Accessors decode values directly from the Parser's raw buffer using the offset of the variable, which means
+// no maps or values are allocated per tick. Values will only be available after a successful call to Parser.NextRaw().
+type Accessor[T AccessorValueType] struct {
+	p      *Parser
+	header headers.VarHeader
+	size   int
+	decode func([]byte) T
+}
+
+// Value of the variable in the current raw tick. For array variables, this is the first item.
+func (a Accessor[T]) Value() T { return a.Index(0) }
+
+// Index retrieves the item at the given index of an array variable in the current raw tick.
+//
+// The zero value is returned when the index is outside of the Len of the variable, or when no raw tick
+// has been read yet. Scalar variables only have an item at index 0.
+func (a Accessor[T]) Index(i int) T {
+	if i < 0 || i >= a.header.Count || len(a.p.buf) == 0 {
+		var zero T
+		return zero
+	}
+
+	start := a.header.Offset + (i * a.size)
+
+	return a.decode(a.p.buf[start : start+a.size])
+}
+
+// Values appends all items of the variable in the current raw tick to dst.
+//
+// Reusing dst between ticks avoids allocating a new slice for every tick.
+func (a Accessor[T]) Values(dst []T) []T {
+	for i := 0; i < a.header.Count; i++ {
+		dst = append(dst, a.Index(i))
+	}
+
+	return dst
+}
+
+// Len is the number of items of the variable. >1 means it is an array.
+func (a Accessor[T]) Len() int { return a.header.Count }
+
+// Header of the variable the accessor reads.
+func (a Accessor[T]) Header() headers.VarHeader { return a.header }
+
+// Uint8 creates an accessor for the given uint8 (Rtype 0) variable.
+func (p *Parser) Uint8(name string) (Accessor[uint8], error) {
+	return newAccessor(p, name, 0, 1, func(b []byte) uint8 { return b[0] })
+}
+
+// Bool creates an accessor for the given boolean (Rtype 1) variable.
+func (p *Parser) Bool(name string) (Accessor[bool], error) {
+	return newAccessor(p, name, 1, 1, func(b []byte) bool { return b[0] > 0 })
+}
+
+// Int creates an accessor for the given int (Rtype 2) variable.
+func (p *Parser) Int(name string) (Accessor[int], error) {
+	return newAccessor(p, name, 2, 4, utilities.Byte4ToInt)
+}
+
+// BitField creates an accessor for the given bitfield (Rtype 3) variable.
+func (p *Parser) BitField(name string) (Accessor[uint32], error) {
+	return newAccessor(p, name, 3, 4, utilities.Byte4ToUint32)
+}
+
+// Float32 creates an accessor for the given float32 (Rtype 4) variable.
+func (p *Parser) Float32(name string) (Accessor[float32], error) {
+	return newAccessor(p, name, 4, 4, utilities.Byte4ToFloat)
+}
+
+// Float64 creates an accessor for the given float64 (Rtype 5) variable.
+func (p *Parser) Float64(name string) (Accessor[float64], error) {
+	return newAccessor(p, name, 5, 8, utilities.Byte8ToFloat)
+}
+
+// newAccessor ensures the variable exists and has the expected type before creating an Accessor.
+func newAccessor[T AccessorValueType](p *Parser, name string, rtype, size int, decode func([]byte) T) (Accessor[T], error) {
+	vh, ok := p.header.VarHeader[name]
+	if !ok {
+		return Accessor[T]{}, fmt.Errorf("variable %s not found in var headers", name)
+	}
+
+	if vh.Rtype != rtype {
+		return Accessor[T]{}, fmt.Errorf("variable %s has rtype %d not %d", name, vh.Rtype, rtype)
+	}
+
+	return Accessor[T]{p: p, header: vh, size: size, decode: decode}, nil
+}
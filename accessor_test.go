@@ -0,0 +1,128 @@
+package ibt
+
+import (
+	"os"
+	"testing"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+func TestAccessors(t *testing.T) {
+	f, err := os.Open(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to open testing file - %v", err)
+		return
+	}
+	defer f.Close()
+
+	testHeaders, err := headers.ParseHeaders(f)
+	if err != nil {
+		t.Errorf("failed to parse header for testing file - %v", err)
+		return
+	}
+
+	t.Run("test accessors match Next()", func(t *testing.T) {
+		p := NewParser(f, testHeaders)
+		tickParser := NewParser(f, testHeaders, "LapCurrentLapTime", "Lap", "OnPitRoad", "SessionTime", "SessionFlags", "Gear")
+
+		lapTime, err := p.Float32("LapCurrentLapTime")
+		if err != nil {
+			t.Errorf("expected Float32() to run without err. received error: %v", err)
+		}
+		lap, _ := p.Int("Lap")
+		onPitRoad, _ := p.Bool("OnPitRoad")
+		sessionTime, _ := p.Float64("SessionTime")
+		sessionFlags, _ := p.BitField("SessionFlags")
+
+		for {
+			raw, hasNext := p.NextRaw()
+			tick, _ := tickParser.Next()
+			if raw.Bytes() == nil {
+				break
+			}
+
+			if lapTime.Value() != tick["LapCurrentLapTime"] || lap.Value() != tick["Lap"] ||
+				onPitRoad.Value() != tick["OnPitRoad"] || sessionTime.Value() != tick["SessionTime"] {
+				t.Errorf("expected accessor values to match tick %v", tick)
+			}
+
+			if sessionFlags.Value() == 0 {
+				t.Errorf("expected session flags to be set for tick %v", tick)
+			}
+
+			if !hasNext {
+				break
+			}
+		}
+	})
+
+	t.Run("test accessor array values", func(t *testing.T) {
+		p := NewParser(f, testHeaders)
+
+		tickParser := NewParser(f, testHeaders, "SteeringWheelTorque_ST")
+
+		torque, err := p.Float32("SteeringWheelTorque_ST")
+		if err != nil {
+			t.Errorf("expected Float32() to run without err. received error: %v", err)
+		}
+
+		if torque.Value() != 0 {
+			t.Errorf("expected the value before the first raw tick to be %d. received %f", 0, torque.Value())
+		}
+
+		p.NextRaw()
+		tick, _ := tickParser.Next()
+		expected := tick["SteeringWheelTorque_ST"].([]float32)
+
+		values := torque.Values(nil)
+		if torque.Len() != 6 || len(values) != torque.Len() {
+			t.Errorf("expected %d values. received %d", 6, len(values))
+		}
+
+		for i := range expected {
+			if values[i] != expected[i] || torque.Index(i) != expected[i] {
+				t.Errorf("expected accessor values to be %v. received %v", expected, values)
+			}
+		}
+
+		if torque.Index(-1) != 0 || torque.Index(torque.Len()) != 0 {
+			t.Errorf("expected out of range indexes to be %d. received %f and %f", 0, torque.Index(-1), torque.Index(torque.Len()))
+		}
+
+		if torque.Header().Name != "SteeringWheelTorque_ST" {
+			t.Errorf("expected accessor header name to be %s. received %s", "SteeringWheelTorque_ST", torque.Header().Name)
+		}
+	})
+
+	t.Run("test accessor invalid variables", func(t *testing.T) {
+		p := NewParser(f, testHeaders)
+
+		if _, err := p.Float32("NotFound"); err == nil {
+			t.Error("expected an error when creating an accessor for a missing variable")
+		}
+
+		if _, err := p.Int("Speed"); err == nil {
+			t.Error("expected an error when creating an int accessor for a float variable")
+		}
+
+		if _, err := p.Uint8("Speed"); err == nil {
+			t.Error("expected an error when creating an uint8 accessor for a float variable")
+		}
+	})
+
+	t.Run("test NextRaw() does not allocate", func(t *testing.T) {
+		p := NewParser(f, testHeaders)
+		speed, _ := p.Float32("Speed")
+		p.NextRaw()
+
+		allocs := testing.AllocsPerRun(100, func() {
+			p.Seek(1)
+			p.NextRaw()
+			_ = speed.Value()
+		})
+
+		if allocs != 0 {
+			t.Errorf("expected NextRaw() to not allocate. received %f allocations per run", allocs)
+		}
+	})
+}
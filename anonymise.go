@@ -0,0 +1,259 @@
+package ibt
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+// Anonymiser replaces the personal details of drivers in the session info of ibt files with pseudonyms.
+//
+// Pseudonyms are derived from the iRacing user and team IDs using a secret key, meaning the same driver
+// receives the same pseudonym across all files anonymised with the same key, while the original identity
+// cannot be recovered without it. Telemetry variables do not contain personal details and are copied as is.
+type Anonymiser struct {
+	key []byte
+}
+
+// NewAnonymiser creates an Anonymiser with the given secret key.
+//
+// The key should be kept private and reused to keep pseudonyms consistent between files.
+func NewAnonymiser(key []byte) (*Anonymiser, error) {
+	if len(key) == 0 {
+		return nil, errors.New("a key is required for anonymising")
+	}
+
+	return &Anonymiser{key: key}, nil
+}
+
+// AnonymiseChange is a single value of the session info that was replaced by the Anonymiser.
+type AnonymiseChange struct {
+	// Path of the field in the session info, for example "DriverInfo.Drivers[0].UserName"
+	Field string
+	// Original value of the field
+	Original interface{}
+	// Value that replaced the original
+	Value interface{}
+}
+
+// AnonymiseReport lists the values that were replaced when anonymising a session.
+type AnonymiseReport struct {
+	Changes []AnonymiseChange
+}
+
+// record adds a change to the report if the value has changed.
+//
+// Values are compared with reflect.DeepEqual, since optional fields of the session info can be decoded as slices or maps.
+func (r *AnonymiseReport) record(field string, original, value interface{}) {
+	if reflect.DeepEqual(original, value) {
+		return
+	}
+
+	r.Changes = append(r.Changes, AnonymiseChange{Field: field, Original: original, Value: value})
+}
+
+// Anonymise writes the stub to w as a new ibt file with anonymised session info.
+//
+// All records of the stub are copied, while the headers are preserved apart from the session info.
+// See AnonymiseSession for the details that are replaced. Fields of the raw session info that are not part of
+// headers.Session are not written, since they may contain personal details.
+func (a *Anonymiser) Anonymise(w io.WriteSeeker, stub Stub) (AnonymiseReport, error) {
+	if stub.header == nil || stub.header.SessionInfo == nil {
+		return AnonymiseReport{}, fmt.Errorf("no session info found for stub %s", stub.Filename())
+	}
+
+	session, report := a.AnonymiseSession(stub.header.SessionInfo)
+
+	header := *stub.header
+	header.SessionInfo = session
+	// Fields that are not part of the session may contain personal details and are not written
+	header.RawSessionInfo = nil
+
+	reader, closeReader, err := stub.reader()
+	if err != nil {
+		return report, err
+	}
+	defer closeReader()
+
+	writer, err := NewWriter(w, &header)
+	if err != nil {
+		return report, fmt.Errorf("failed to create writer for stub %s: %v", stub.Filename(), err)
+	}
+
+	parser := NewParser(reader, &header)
+
+	for {
+		raw, hasNext := parser.NextRaw()
+		if raw.buf == nil {
+			break
+		}
+
+		if err := writer.WriteRaw(raw); err != nil {
+			return report, fmt.Errorf("failed to anonymise stub %s: %v", stub.Filename(), err)
+		}
+
+		if !hasNext {
+			break
+		}
+	}
+
+	return report, writer.Close()
+}
+
+// AnonymiseSession returns a copy of the session with the personal details of all drivers replaced.
+//
+// For each driver, the UserName, UserID, TeamName, TeamID, AbbrevName and Initials are replaced with
+// pseudonyms, while the IRating, LicString, LicColor, LicLevel, LicSubLevel and design strings of the car,
+// number, helmet and suit are cleared. The DriverUserID is replaced with the pseudonym of the user, and names
+// of radio frequencies, other than the iRacing channels starting with "@", are replaced with the pseudonym of
+// the matching driver or team. All other fields are kept. Club and division details are not part of
+// headers.Session and are only found in the raw session info, which Anonymise does not write.
+// The pace car is left as is. The original session is not modified.
+func (a *Anonymiser) AnonymiseSession(session *headers.Session) (*headers.Session, AnonymiseReport) {
+	var report AnonymiseReport
+
+	anonymised := *session
+	anonymised.DriverInfo.Drivers = make([]headers.Drivers, len(session.DriverInfo.Drivers))
+	copy(anonymised.DriverInfo.Drivers, session.DriverInfo.Drivers)
+
+	// Pseudonyms of driver and team names, used for renaming radio frequencies
+	names := make(map[string]string)
+
+	for idx := range anonymised.DriverInfo.Drivers {
+		driver := &anonymised.DriverInfo.Drivers[idx]
+		if driver.CarIsPaceCar == 1 {
+			continue
+		}
+
+		a.anonymiseDriver(driver, fmt.Sprintf("DriverInfo.Drivers[%d]", idx), names, &report)
+	}
+
+	driverUserID := session.DriverInfo.DriverUserID
+	if driverUserID > 0 {
+		anonymised.DriverInfo.DriverUserID = a.id("user", strconv.Itoa(driverUserID))
+		report.record("DriverInfo.DriverUserID", driverUserID, anonymised.DriverInfo.DriverUserID)
+	}
+
+	anonymised.RadioInfo.Radios = make([]headers.Radios, len(session.RadioInfo.Radios))
+	for radioIdx, radio := range session.RadioInfo.Radios {
+		radio.Frequencies = append([]headers.Frequencies(nil), radio.Frequencies...)
+
+		for freqIdx := range radio.Frequencies {
+			freq := &radio.Frequencies[freqIdx]
+			if freq.FrequencyName == "" || strings.HasPrefix(freq.FrequencyName, "@") {
+				continue
+			}
+
+			name, ok := names[freq.FrequencyName]
+			if !ok {
+				name = "Frequency " + a.code("frequency", freq.FrequencyName)
+			}
+
+			field := fmt.Sprintf("RadioInfo.Radios[%d].Frequencies[%d].FrequencyName", radioIdx, freqIdx)
+			report.record(field, freq.FrequencyName, name)
+			freq.FrequencyName = name
+		}
+
+		anonymised.RadioInfo.Radios[radioIdx] = radio
+	}
+
+	return &anonymised, report
+}
+
+// anonymiseDriver replaces the personal details of the driver and adds the pseudonyms of it's names to names.
+func (a *Anonymiser) anonymiseDriver(driver *headers.Drivers, path string, names map[string]string, report *AnonymiseReport) {
+	userKey := driver.UserName
+	if driver.UserID > 0 {
+		userKey = strconv.Itoa(driver.UserID)
+	}
+
+	code := a.code("user", userKey)
+	userName := "Driver " + code
+
+	teamName := userName
+	// Teams of a single driver are named after the driver, as is done by iRacing
+	if driver.TeamName != driver.UserName {
+		teamKey := driver.TeamName
+		if driver.TeamID > 0 {
+			teamKey = strconv.Itoa(driver.TeamID)
+		}
+		teamName = "Team " + a.code("team", teamKey)
+	}
+
+	if driver.UserName != "" {
+		names[driver.UserName] = userName
+	}
+	if driver.TeamName != "" {
+		names[driver.TeamName] = teamName
+	}
+
+	replace := func(field string, target interface{}, value interface{}) {
+		switch t := target.(type) {
+		case *string:
+			report.record(path+"."+field, *t, value)
+			*t = value.(string)
+		case *int:
+			report.record(path+"."+field, *t, value)
+			*t = value.(int)
+		case *interface{}:
+			// Optional fields are only replaced when they are present
+			if *t == nil {
+				return
+			}
+			report.record(path+"."+field, *t, value)
+			*t = value
+		}
+	}
+
+	replace("UserName", &driver.UserName, userName)
+	if driver.UserID > 0 {
+		replace("UserID", &driver.UserID, a.id("user", userKey))
+	}
+	replace("TeamName", &driver.TeamName, teamName)
+	if driver.TeamID > 0 {
+		replace("TeamID", &driver.TeamID, a.id("team", strconv.Itoa(driver.TeamID)))
+	}
+	replace("AbbrevName", &driver.AbbrevName, code)
+	replace("Initials", &driver.Initials, code[:2])
+	replace("IRating", &driver.IRating, 0)
+	replace("LicString", &driver.LicString, "")
+	replace("LicColor", &driver.LicColor, "")
+	replace("LicLevel", &driver.LicLevel, 0)
+	replace("LicSubLevel", &driver.LicSubLevel, 0)
+	replace("CarDesignStr", &driver.CarDesignStr, "")
+	replace("CarNumberDesignStr", &driver.CarNumberDesignStr, "")
+	replace("HelmetDesignStr", &driver.HelmetDesignStr, "")
+	replace("SuitDesignStr", &driver.SuitDesignStr, "")
+}
+
+// sum returns the keyed hash of the value within the given namespace.
+func (a *Anonymiser) sum(namespace, value string) []byte {
+	mac := hmac.New(sha256.New, a.key)
+	mac.Write([]byte(namespace + ":" + value))
+
+	return mac.Sum(nil)
+}
+
+// code returns a short pseudonym of the value, for example "3FA2C1".
+func (a *Anonymiser) code(namespace, value string) string {
+	return strings.ToUpper(hex.EncodeToString(a.sum(namespace, value)[:3]))
+}
+
+// id returns a positive numeric pseudonym of the value.
+func (a *Anonymiser) id(namespace, value string) int {
+	id := int(binary.BigEndian.Uint32(a.sum(namespace, value)) & 0x7fffffff)
+	if id == 0 {
+		return 1
+	}
+
+	return id
+}
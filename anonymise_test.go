@@ -0,0 +1,204 @@
+package ibt
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+func TestAnonymiser(t *testing.T) {
+	stubs, err := ParseLazyStubs(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to parse stubs for testing file - %v", err)
+	}
+	original := stubs[0]
+	session := original.Headers().SessionInfo
+
+	anonymiser, err := NewAnonymiser([]byte("secret"))
+	if err != nil {
+		t.Fatalf("expected NewAnonymiser() to run without err. received error: %v", err)
+	}
+
+	t.Run("test NewAnonymiser empty key", func(t *testing.T) {
+		if _, err := NewAnonymiser(nil); err == nil {
+			t.Error("expected NewAnonymiser() to return an error for an empty key")
+		}
+	})
+
+	t.Run("test AnonymiseSession", func(t *testing.T) {
+		anonymised, report := anonymiser.AnonymiseSession(session)
+
+		driver := anonymised.DriverInfo.Drivers[0]
+		if !strings.HasPrefix(driver.UserName, "Driver ") || driver.TeamName != driver.UserName {
+			t.Errorf("expected a driver pseudonym as user and team name. received %s and %s", driver.UserName, driver.TeamName)
+		}
+
+		if driver.UserID == 450313 || driver.UserID <= 0 || anonymised.DriverInfo.DriverUserID != driver.UserID {
+			t.Errorf("expected a positive pseudonym user id matching the DriverUserID. received %d and %d", driver.UserID, anonymised.DriverInfo.DriverUserID)
+		}
+
+		if driver.IRating != 0 || driver.LicString != "" || driver.LicColor != "" || driver.LicLevel != 0 || driver.LicSubLevel != 0 || driver.HelmetDesignStr != "" || driver.CarDesignStr != "" {
+			t.Errorf("expected rating, license and designs to be cleared. received %+v", driver)
+		}
+
+		if driver.AbbrevName != nil || driver.Initials != nil {
+			t.Errorf("expected missing abbreviated name and initials to remain missing. received %v and %v", driver.AbbrevName, driver.Initials)
+		}
+
+		if driver.CarPath != "mercedesw13" {
+			t.Errorf("expected car to be preserved. received %s", driver.CarPath)
+		}
+
+		if session.DriverInfo.Drivers[0].UserName != "George v Rensburg" || session.DriverInfo.DriverUserID != 450313 {
+			t.Error("expected original session to be unmodified")
+		}
+
+		if !reflect.DeepEqual(anonymised.RadioInfo, session.RadioInfo) {
+			t.Error("expected iRacing radio frequencies to be preserved")
+		}
+
+		// UserName, UserID, TeamName, IRating, 4 license fields, 4 design strings and DriverUserID
+		if len(report.Changes) != 13 {
+			t.Errorf("expected %d changes. received %d: %+v", 13, len(report.Changes), report.Changes)
+		}
+
+		expected := AnonymiseChange{Field: "DriverInfo.Drivers[0].UserName", Original: "George v Rensburg", Value: driver.UserName}
+		if report.Changes[0] != expected {
+			t.Errorf("expected change %+v. received %+v", expected, report.Changes[0])
+		}
+	})
+
+	t.Run("test AnonymiseSession deterministic", func(t *testing.T) {
+		first, _ := anonymiser.AnonymiseSession(session)
+		second, _ := anonymiser.AnonymiseSession(session)
+		if !reflect.DeepEqual(first, second) {
+			t.Error("expected the same pseudonyms for the same key")
+		}
+
+		other, _ := NewAnonymiser([]byte("other"))
+		third, _ := other.AnonymiseSession(session)
+		if third.DriverInfo.Drivers[0].UserName == first.DriverInfo.Drivers[0].UserName {
+			t.Error("expected different pseudonyms for a different key")
+		}
+	})
+
+	t.Run("test AnonymiseSession teams and frequencies", func(t *testing.T) {
+		multi := *session
+		multi.DriverInfo.Drivers = []headers.Drivers{
+			{UserName: "Jane Doe", UserID: 1, TeamName: "Fast Team", TeamID: 7, AbbrevName: "Doe, J", Initials: "JD"},
+			{UserName: "John Doe", UserID: 2, TeamName: "Fast Team", TeamID: 7},
+			{UserName: "Pace Car", UserID: -1, CarIsPaceCar: 1},
+		}
+		multi.RadioInfo.Radios = []headers.Radios{
+			{Frequencies: []headers.Frequencies{{FrequencyName: "@ALLTEAMS"}, {FrequencyName: "Fast Team"}, {FrequencyName: "Private"}}},
+		}
+
+		anonymised, _ := anonymiser.AnonymiseSession(&multi)
+		drivers := anonymised.DriverInfo.Drivers
+
+		if drivers[0].TeamName != drivers[1].TeamName || !strings.HasPrefix(drivers[0].TeamName, "Team ") || drivers[0].TeamID != drivers[1].TeamID {
+			t.Errorf("expected drivers of the same team to share it's pseudonym. received %s and %s", drivers[0].TeamName, drivers[1].TeamName)
+		}
+
+		if drivers[0].UserName == drivers[1].UserName {
+			t.Error("expected different pseudonyms for different drivers")
+		}
+
+		code := strings.TrimPrefix(drivers[0].UserName, "Driver ")
+		if drivers[0].AbbrevName != code || drivers[0].Initials != code[:2] {
+			t.Errorf("expected abbreviated name %s and initials %s. received %v and %v", code, code[:2], drivers[0].AbbrevName, drivers[0].Initials)
+		}
+
+		if !reflect.DeepEqual(drivers[2], multi.DriverInfo.Drivers[2]) {
+			t.Error("expected pace car to be preserved")
+		}
+
+		frequencies := anonymised.RadioInfo.Radios[0].Frequencies
+		if frequencies[0].FrequencyName != "@ALLTEAMS" || frequencies[1].FrequencyName != drivers[0].TeamName ||
+			!strings.HasPrefix(frequencies[2].FrequencyName, "Frequency ") {
+			t.Errorf("expected frequency names to be anonymised. received %+v", frequencies)
+		}
+
+		if multi.RadioInfo.Radios[0].Frequencies[1].FrequencyName != "Fast Team" {
+			t.Error("expected original frequencies to be unmodified")
+		}
+	})
+
+	t.Run("test AnonymiseSession uncomparable values", func(t *testing.T) {
+		uncomparable := *session
+		uncomparable.DriverInfo.Drivers = []headers.Drivers{
+			{UserName: "Jane Doe", UserID: 1, AbbrevName: []interface{}{"Doe", "J"}, Initials: map[string]interface{}{"first": "J"}},
+		}
+
+		anonymised, report := anonymiser.AnonymiseSession(&uncomparable)
+
+		code := strings.TrimPrefix(anonymised.DriverInfo.Drivers[0].UserName, "Driver ")
+		if anonymised.DriverInfo.Drivers[0].AbbrevName != code {
+			t.Errorf("expected abbreviated name %s. received %v", code, anonymised.DriverInfo.Drivers[0].AbbrevName)
+		}
+
+		fields := make(map[string]bool)
+		for _, change := range report.Changes {
+			fields[change.Field] = true
+		}
+
+		if !fields["DriverInfo.Drivers[0].AbbrevName"] || !fields["DriverInfo.Drivers[0].Initials"] {
+			t.Errorf("expected changes of the abbreviated name and initials. received %+v", report.Changes)
+		}
+
+		report = AnonymiseReport{}
+		report.record("AbbrevName", []interface{}{"Doe", "J"}, []interface{}{"Doe", "J"})
+		report.record("Initials", []interface{}{"J"}, []interface{}{"D"})
+		if len(report.Changes) != 1 || report.Changes[0].Field != "Initials" {
+			t.Errorf("expected only changed values to be recorded. received %+v", report.Changes)
+		}
+	})
+
+	t.Run("test Anonymise", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "anonymised.ibt")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("failed to create file %s - %v", path, err)
+		}
+		defer f.Close()
+
+		report, err := anonymiser.Anonymise(f, original)
+		if err != nil {
+			t.Errorf("expected Anonymise() to run without err. received error: %v", err)
+			return
+		}
+
+		if len(report.Changes) == 0 {
+			t.Error("expected changes to be reported")
+		}
+
+		stubs, err := ParseLazyStubs(path)
+		if err != nil {
+			t.Errorf("expected anonymised file to be parsed without err. received error: %v", err)
+			return
+		}
+
+		header := stubs[0].Headers()
+		if header.SessionInfo.GetDriver().UserName == "George v Rensburg" {
+			t.Error("expected driver name to be anonymised")
+		}
+
+		if _, ok := header.RawSessionInfo.Lookup("DriverInfo.Drivers[0].FaceType"); ok {
+			t.Error("expected fields that are not part of the session to be removed")
+		}
+
+		if !reflect.DeepEqual(header.DiskHeader, original.Headers().DiskHeader) {
+			t.Errorf("expected disk header %+v. received %+v", original.Headers().DiskHeader, header.DiskHeader)
+		}
+
+		expected, _ := LoadChannels(original, "Speed")
+		received, err := LoadChannels(stubs[0], "Speed")
+		if err != nil || !reflect.DeepEqual(expected.Columns, received.Columns) {
+			t.Errorf("expected telemetry to be preserved. received error: %v", err)
+		}
+	})
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/teamjorge/ibt"
+	"github.com/teamjorge/ibt/export"
+	"github.com/teamjorge/ibt/headers"
+)
+
+// dumpTick is a single line of the dump command's JSON output.
+type dumpTick struct {
+	Tick   int                    `json:"tick"`
+	Values map[string]interface{} `json:"values"`
+}
+
+// runDump prints the whitelisted variables for a range of ticks of the given file.
+//
+// JSON output is written as a line per tick, so that large ranges can be streamed.
+func runDump(args []string, stdout, stderr io.Writer) error {
+	flags, jsonOutput := newFlagSet("dump", "file", stderr)
+	vars := flags.String("vars", "*", "comma separated variables to print, or * for all variables")
+	start := flags.Int("start", 0, "index of the first tick to print")
+	end := flags.Int("end", 0, "index of the tick after the last tick to print. 0 prints until the end of the file")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected a single file. received %d", flags.NArg())
+	}
+
+	if *start < 0 || (*end != 0 && *end <= *start) {
+		return fmt.Errorf("invalid tick range %d to %d", *start, *end)
+	}
+
+	stubs, err := ibt.ParseLazyStubs(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer stubs.Close()
+
+	header := stubs[0].Headers()
+
+	whitelist := strings.Split(*vars, ",")
+	for i := range whitelist {
+		whitelist[i] = strings.TrimSpace(whitelist[i])
+	}
+
+	if *vars == "" || *vars == "*" {
+		whitelist = headers.AvailableVars(header.VarHeader)
+		sort.Strings(whitelist)
+	}
+
+	for _, name := range whitelist {
+		if _, ok := header.VarHeader[name]; !ok {
+			return fmt.Errorf("variable %s not found in %s", name, flags.Arg(0))
+		}
+	}
+
+	parser, err := stubs[0].Parser(whitelist...)
+	if err != nil {
+		return err
+	}
+	parser.SeekRange(*start, *end)
+
+	var table io.Writer = stdout
+	enc := json.NewEncoder(stdout)
+
+	if !*jsonOutput {
+		tw := newTable(stdout)
+		defer tw.Flush()
+		table = tw
+
+		fmt.Fprintf(table, "TICK\t%s\n", strings.Join(whitelist, "\t"))
+	}
+
+	for tickIdx := *start; ; tickIdx++ {
+		tick, hasNext := parser.Next()
+		if tick == nil {
+			break
+		}
+
+		if *jsonOutput {
+			line := dumpTick{Tick: tickIdx, Values: make(map[string]interface{}, len(whitelist))}
+			for _, name := range whitelist {
+				line.Values[name] = export.JSONValue(tick[name])
+			}
+
+			if err := enc.Encode(line); err != nil {
+				return err
+			}
+		} else {
+			values := make([]string, 0, len(whitelist))
+			for _, name := range whitelist {
+				values = append(values, fmt.Sprint(tick[name]))
+			}
+
+			fmt.Fprintf(table, "%d\t%s\n", tickIdx, strings.Join(values, "\t"))
+		}
+
+		if !hasNext {
+			break
+		}
+	}
+
+	return nil
+}
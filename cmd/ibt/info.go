@@ -0,0 +1,126 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"path/filepath"
+	"time"
+
+	"github.com/teamjorge/ibt"
+	"github.com/teamjorge/ibt/headers"
+)
+
+// fileInfo is the output of the info command for a single file.
+type fileInfo struct {
+	File            string                   `json:"file"`
+	TelemetryHeader *headers.TelemetryHeader `json:"telemetry_header"`
+	DiskHeader      *headers.DiskHeader      `json:"disk_header"`
+	StartDate       time.Time                `json:"start_date"`
+	Track           string                   `json:"track"`
+	TrackConfig     string                   `json:"track_config"`
+	TrackLength     string                   `json:"track_length"`
+	Car             string                   `json:"car"`
+	Driver          string                   `json:"driver"`
+	DriverUserID    int                      `json:"driver_user_id"`
+	EventType       string                   `json:"event_type"`
+	SessionID       int                      `json:"session_id"`
+	SubSessionID    int                      `json:"sub_session_id"`
+	Sessions        []string                 `json:"sessions"`
+}
+
+// runInfo prints the headers and session details of every given file.
+func runInfo(args []string, stdout, stderr io.Writer) error {
+	flags, jsonOutput := newFlagSet("info", "file...", stderr)
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("no files provided")
+	}
+
+	stubs, err := ibt.ParseLazyStubs(flags.Args()...)
+	if err != nil {
+		return err
+	}
+	defer stubs.Close()
+
+	infos := make([]fileInfo, 0, len(stubs))
+	for _, stub := range stubs {
+		infos = append(infos, newFileInfo(stub))
+	}
+
+	if *jsonOutput {
+		return writeJSON(stdout, infos)
+	}
+
+	for i, info := range infos {
+		if i > 0 {
+			fmt.Fprintln(stdout)
+		}
+
+		table := newTable(stdout)
+		fmt.Fprintf(table, "File:\t%s\n", info.File)
+		fmt.Fprintf(table, "Track:\t%s (%s, %s)\n", info.Track, info.TrackConfig, info.TrackLength)
+		fmt.Fprintf(table, "Car:\t%s\n", info.Car)
+		fmt.Fprintf(table, "Driver:\t%s (%d)\n", info.Driver, info.DriverUserID)
+		fmt.Fprintf(table, "Event:\t%s\n", info.EventType)
+		fmt.Fprintf(table, "Session ID:\t%d\n", info.SessionID)
+		fmt.Fprintf(table, "Sub Session ID:\t%d\n", info.SubSessionID)
+		fmt.Fprintf(table, "Sessions:\t%v\n", info.Sessions)
+		fmt.Fprintf(table, "Version:\t%d\n", info.TelemetryHeader.Version)
+		fmt.Fprintf(table, "Tick Rate:\t%d\n", info.TelemetryHeader.TickRate)
+		fmt.Fprintf(table, "Variables:\t%d\n", info.TelemetryHeader.NumVars)
+		fmt.Fprintf(table, "Buffer Length:\t%d\n", info.TelemetryHeader.BufLen)
+
+		if info.DiskHeader != nil {
+			fmt.Fprintf(table, "Start Date:\t%s\n", info.StartDate.Format(time.RFC3339))
+			fmt.Fprintf(table, "Start Time:\t%.3f\n", info.DiskHeader.StartTime)
+			fmt.Fprintf(table, "End Time:\t%.3f\n", info.DiskHeader.EndTime)
+			fmt.Fprintf(table, "Laps:\t%d\n", info.DiskHeader.LapCount)
+			fmt.Fprintf(table, "Records:\t%d\n", info.DiskHeader.RecordCount)
+		}
+
+		if err := table.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// newFileInfo collects the info of the stub.
+func newFileInfo(stub ibt.Stub) fileInfo {
+	header := stub.Headers()
+	session := header.SessionInfo
+
+	info := fileInfo{
+		File:            filepath.Base(stub.Filename()),
+		TelemetryHeader: header.TelemetryHeader,
+		DiskHeader:      header.DiskHeader,
+		Track:           session.WeekendInfo.TrackDisplayName,
+		TrackConfig:     session.WeekendInfo.TrackConfigName,
+		TrackLength:     session.WeekendInfo.TrackLength,
+		EventType:       session.WeekendInfo.EventType,
+		SessionID:       session.WeekendInfo.SessionID,
+		SubSessionID:    session.WeekendInfo.SubSessionID,
+		Sessions:        make([]string, 0, len(session.SessionInfo.Sessions)),
+	}
+
+	if header.DiskHeader != nil {
+		info.StartDate = stub.Time().UTC()
+	}
+
+	if driver := session.GetDriver(); driver != nil {
+		info.Car = driver.CarScreenName
+		info.Driver = driver.UserName
+		info.DriverUserID = driver.UserID
+	}
+
+	for _, s := range session.SessionInfo.Sessions {
+		info.Sessions = append(info.Sessions, s.SessionName)
+	}
+
+	return info
+}
@@ -0,0 +1,117 @@
+// Command ibt inspects iRacing telemetry (ibt) files.
+//
+// Usage:
+//
+//	ibt info [-json] file...
+//	ibt vars [-json] [-filter regex] file
+//	ibt dump [-json] [-vars Speed,Lap] [-start tick] [-end tick] file
+//	ibt trim [-json] -o output [-start tick] [-end tick] [-lap number] [-from seconds -to seconds] file
+//	ibt split [-json] [-o directory] file
+//
+// Output is written as aligned tables by default and as JSON when the -json flag is provided. The trim and
+// split commands write new ibt files and print the paths of the files that were written, with the number of
+// records for trim.
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+)
+
+const usage = `Usage: ibt <command> [flags] file...
+
+Commands:
+  info    Print the headers and session details of ibt files
+  vars    Print the telemetry variables of an ibt file
+  dump    Print telemetry ticks of an ibt file
+  trim    Write a tick range, lap or session time range of an ibt file to a new file
+  split   Write every lap of an ibt file to a new file
+
+Run 'ibt <command> -h' for the flags of a command.
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, flag.ErrHelp) {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(1)
+	}
+}
+
+// run the command given by args, writing output to stdout and usage to stderr.
+func run(args []string, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return errors.New("no command provided")
+	}
+
+	switch args[0] {
+	case "info":
+		return runInfo(args[1:], stdout, stderr)
+	case "vars":
+		return runVars(args[1:], stdout, stderr)
+	case "dump":
+		return runDump(args[1:], stdout, stderr)
+	case "trim":
+		return runTrim(args[1:], stdout, stderr)
+	case "split":
+		return runSplit(args[1:], stdout, stderr)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(stdout, usage)
+		return nil
+	}
+
+	fmt.Fprint(stderr, usage)
+	return fmt.Errorf("unknown command %q", args[0])
+}
+
+// newFlagSet creates the flag set of a command with the common -json flag.
+func newFlagSet(name, args string, stderr io.Writer) (*flag.FlagSet, *bool) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintf(stderr, "Usage: ibt %s [flags] %s\n\nFlags:\n", name, args)
+		flags.PrintDefaults()
+	}
+
+	jsonOutput := flags.Bool("json", false, "write output as JSON")
+
+	return flags, jsonOutput
+}
+
+// newTable creates a tabwriter for aligned table output.
+func newTable(w io.Writer) *tabwriter.Writer { return tabwriter.NewWriter(w, 0, 0, 2, ' ', 0) }
+
+// writeJSON writes the value as indented JSON.
+func writeJSON(w io.Writer, value interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(value)
+}
+
+// rtypeName is the display name of a variable type.
+func rtypeName(rtype int) string {
+	switch rtype {
+	case 0:
+		return "char"
+	case 1:
+		return "bool"
+	case 2:
+		return "int"
+	case 3:
+		return "bitfield"
+	case 4:
+		return "float"
+	case 5:
+		return "double"
+	}
+
+	return fmt.Sprintf("unknown(%d)", rtype)
+}
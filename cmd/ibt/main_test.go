@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teamjorge/ibt"
+)
+
+const testFile = "../../.testing/valid_test_file.ibt"
+
+func TestRun(t *testing.T) {
+	t.Run("test run no command", func(t *testing.T) {
+		if err := run(nil, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+			t.Error("expected run() to return an error when no command is provided")
+		}
+	})
+
+	t.Run("test run unknown command", func(t *testing.T) {
+		stderr := new(bytes.Buffer)
+		if err := run([]string{"unknown"}, new(bytes.Buffer), stderr); err == nil {
+			t.Error("expected run() to return an error for an unknown command")
+		}
+
+		if !strings.HasPrefix(stderr.String(), "Usage: ibt") {
+			t.Errorf("expected usage to be printed. received %s", stderr.String())
+		}
+	})
+}
+
+func TestInfo(t *testing.T) {
+	t.Run("test info table", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"info", testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected info to run without err. received error: %v", err)
+			return
+		}
+
+		for _, expected := range []string{"Red Bull Ring (Grand Prix, 4.28 km)", "George v Rensburg (450313)", "Records:         390"} {
+			if !strings.Contains(stdout.String(), expected) {
+				t.Errorf("expected info output to contain %q. received:\n%s", expected, stdout.String())
+			}
+		}
+	})
+
+	t.Run("test info json", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"info", "-json", testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected info to run without err. received error: %v", err)
+			return
+		}
+
+		var infos []fileInfo
+		if err := json.Unmarshal(stdout.Bytes(), &infos); err != nil {
+			t.Errorf("expected info output to be valid json. received error: %v", err)
+			return
+		}
+
+		if len(infos) != 1 || infos[0].Car != "Mercedes-AMG W13 E Performance" || infos[0].DiskHeader.RecordCount != 390 {
+			t.Errorf("expected info of the testing file. received %+v", infos)
+		}
+	})
+
+	t.Run("test info no files", func(t *testing.T) {
+		if err := run([]string{"info"}, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+			t.Error("expected info to return an error when no files are provided")
+		}
+	})
+}
+
+func TestVars(t *testing.T) {
+	t.Run("test vars filter", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"vars", "-filter", "^(Speed|Gear)$", testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected vars to run without err. received error: %v", err)
+			return
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		if len(lines) != 3 || !strings.HasPrefix(lines[1], "Gear") || !strings.HasPrefix(lines[2], "Speed") {
+			t.Errorf("expected a header and rows for Gear and Speed. received:\n%s", stdout.String())
+		}
+	})
+
+	t.Run("test vars json", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"vars", "-json", testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected vars to run without err. received error: %v", err)
+			return
+		}
+
+		var vars []varInfo
+		if err := json.Unmarshal(stdout.Bytes(), &vars); err != nil {
+			t.Errorf("expected vars output to be valid json. received error: %v", err)
+			return
+		}
+
+		if len(vars) != 276 {
+			t.Errorf("expected %d vars. received %d", 276, len(vars))
+		}
+	})
+
+	t.Run("test vars invalid filter", func(t *testing.T) {
+		if err := run([]string{"vars", "-filter", "(", testFile}, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+			t.Error("expected vars to return an error for an invalid filter")
+		}
+	})
+}
+
+func TestDump(t *testing.T) {
+	t.Run("test dump table", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"dump", "-vars", "Lap,Gear", "-start", "5", "-end", "8", testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected dump to run without err. received error: %v", err)
+			return
+		}
+
+		lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+		if len(lines) != 4 || strings.Fields(lines[1])[0] != "5" || strings.Fields(lines[3])[1] != "9" {
+			t.Errorf("expected a header and ticks 5 to 7. received:\n%s", stdout.String())
+		}
+	})
+
+	t.Run("test dump vars with spaces", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"dump", "-vars", "Lap, Gear", "-start", "5", "-end", "6", testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected dump to run without err. received error: %v", err)
+			return
+		}
+
+		if header := strings.Fields(strings.Split(stdout.String(), "\n")[0]); len(header) != 3 || header[2] != "Gear" {
+			t.Errorf("expected a header of TICK, Lap and Gear. received %v", header)
+		}
+	})
+
+	t.Run("test dump json", func(t *testing.T) {
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"dump", "-json", "-vars", "Speed", "-start", "380", testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected dump to run without err. received error: %v", err)
+			return
+		}
+
+		count := 0
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			var line dumpTick
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				t.Errorf("expected dump line to be valid json. received error: %v", err)
+				return
+			}
+
+			if line.Tick != 380+count {
+				t.Errorf("expected tick %d. received %d", 380+count, line.Tick)
+			}
+			count++
+		}
+
+		if count != 10 {
+			t.Errorf("expected %d ticks to be dumped. received %d", 10, count)
+		}
+	})
+
+	t.Run("test dump invalid arguments", func(t *testing.T) {
+		if err := run([]string{"dump", "-vars", "NotFound", testFile}, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+			t.Error("expected dump to return an error for a missing variable")
+		}
+
+		if err := run([]string{"dump", "-start", "10", "-end", "5", testFile}, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+			t.Error("expected dump to return an error for an invalid range")
+		}
+	})
+}
+
+func TestTrim(t *testing.T) {
+	t.Run("test trim tick range", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "trimmed.ibt")
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"trim", "-o", output, "-start", "10", "-end", "70", testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected trim to run without err. received error: %v", err)
+			return
+		}
+
+		if expected := fmt.Sprintf("%s (%d records)\n", output, 60); stdout.String() != expected {
+			t.Errorf("expected output %q. received %q", expected, stdout.String())
+		}
+
+		stubs, err := ibt.ParseLazyStubs(output)
+		if err != nil {
+			t.Errorf("expected trimmed file to be parsed without err. received error: %v", err)
+			return
+		}
+
+		if records := stubs[0].Headers().DiskHeader.RecordCount; records != 60 {
+			t.Errorf("expected %d records. received %d", 60, records)
+		}
+	})
+
+	t.Run("test trim json", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "trimmed.ibt")
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"trim", "-json", "-o", output, "-start", "10", "-end", "70", testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected trim to run without err. received error: %v", err)
+			return
+		}
+
+		var result trimResult
+		if err := json.Unmarshal(stdout.Bytes(), &result); err != nil {
+			t.Errorf("expected output to be valid json. received error: %v", err)
+			return
+		}
+
+		if result.File != output || result.Records != 60 {
+			t.Errorf("expected %d records written to %s. received %+v", 60, output, result)
+		}
+	})
+
+	t.Run("test trim same file", func(t *testing.T) {
+		input := filepath.Join(t.TempDir(), "input.ibt")
+		data, err := os.ReadFile(testFile)
+		if err != nil {
+			t.Fatalf("failed to read testing file - %v", err)
+		}
+		if err := os.WriteFile(input, data, 0644); err != nil {
+			t.Fatalf("failed to write testing file - %v", err)
+		}
+
+		if err := run([]string{"trim", "-o", input, "-start", "10", input}, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+			t.Error("expected trim to return an error when the output is the input")
+		}
+
+		if info, err := os.Stat(input); err != nil || info.Size() != int64(len(data)) {
+			t.Errorf("expected input to be left unchanged. received error: %v", err)
+		}
+	})
+
+	t.Run("test trim lap", func(t *testing.T) {
+		output := filepath.Join(t.TempDir(), "trimmed.ibt")
+		if err := run([]string{"trim", "-o", output, "-lap", "9", testFile}, new(bytes.Buffer), new(bytes.Buffer)); err != nil {
+			t.Errorf("expected trim to run without err. received error: %v", err)
+		}
+
+		if err := run([]string{"trim", "-o", output, "-lap", "3", testFile}, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+			t.Error("expected trim to return an error for a missing lap")
+		}
+
+		if _, err := os.Stat(output); !os.IsNotExist(err) {
+			t.Error("expected output to be removed after a failed trim")
+		}
+	})
+
+	t.Run("test trim invalid arguments", func(t *testing.T) {
+		if err := run([]string{"trim", testFile}, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+			t.Error("expected trim to return an error when no output is provided")
+		}
+
+		output := filepath.Join(t.TempDir(), "trimmed.ibt")
+		if err := run([]string{"trim", "-o", output, "-from", "10", testFile}, new(bytes.Buffer), new(bytes.Buffer)); err == nil {
+			t.Error("expected trim to return an error for an invalid time range")
+		}
+	})
+}
+
+func TestSplit(t *testing.T) {
+	t.Run("test split json", func(t *testing.T) {
+		dir := t.TempDir()
+		stdout := new(bytes.Buffer)
+		if err := run([]string{"split", "-json", "-o", dir, testFile}, stdout, new(bytes.Buffer)); err != nil {
+			t.Errorf("expected split to run without err. received error: %v", err)
+			return
+		}
+
+		var files []string
+		if err := json.Unmarshal(stdout.Bytes(), &files); err != nil {
+			t.Errorf("expected output to be valid json. received error: %v", err)
+			return
+		}
+
+		expected := []string{filepath.Join(dir, "valid_test_file_lap9.ibt")}
+		if !reflect.DeepEqual(files, expected) {
+			t.Errorf("expected files %v. received %v", expected, files)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/teamjorge/ibt"
+)
+
+// trimResult is the output of the trim command.
+type trimResult struct {
+	File    string `json:"file"`
+	Records int    `json:"records"`
+}
+
+// runTrim writes a range of ticks, a single lap, or a session time range of the given file to a new file.
+func runTrim(args []string, stdout, stderr io.Writer) error {
+	flags, jsonOutput := newFlagSet("trim", "-o output file", stderr)
+	output := flags.String("o", "", "path of the trimmed file")
+	start := flags.Int("start", 0, "index of the first tick to keep")
+	end := flags.Int("end", 0, "index of the tick after the last tick to keep. 0 keeps until the end of the file")
+	lap := flags.Int("lap", -1, "number of the lap to keep, instead of a tick range")
+	from := flags.Float64("from", -1, "session time in seconds of the first tick to keep, instead of a tick range")
+	to := flags.Float64("to", -1, "session time in seconds to keep ticks until, used with -from")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 || *output == "" {
+		flags.Usage()
+		return fmt.Errorf("expected a single file and an output path. received %d files", flags.NArg())
+	}
+
+	// The input is read while the output is written, meaning the output may not replace it
+	if sameFile(flags.Arg(0), *output) {
+		return fmt.Errorf("output %s is the same file as the input", *output)
+	}
+
+	stubs, err := ibt.ParseLazyStubs(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	stub := stubs[0]
+
+	f, err := os.Create(*output)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %v", *output, err)
+	}
+
+	switch {
+	case *lap >= 0:
+		err = trimLap(f, stub, *lap)
+	case *from >= 0:
+		err = ibt.TrimTime(f, stub, *from, *to)
+	default:
+		err = ibt.Trim(f, stub, *start, *end)
+	}
+	if err != nil {
+		f.Close()
+		os.Remove(*output)
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to close file %s: %v", *output, err)
+	}
+
+	trimmed, err := ibt.ParseLazyStubs(*output)
+	if err != nil {
+		return err
+	}
+
+	result := trimResult{File: *output, Records: trimmed[0].Headers().DiskHeader.RecordCount}
+
+	if *jsonOutput {
+		return writeJSON(stdout, result)
+	}
+
+	fmt.Fprintf(stdout, "%s (%d records)\n", result.File, result.Records)
+
+	return nil
+}
+
+// sameFile returns whether both paths refer to the same existing file.
+func sameFile(a, b string) bool {
+	infoA, err := os.Stat(a)
+	if err != nil {
+		return false
+	}
+
+	infoB, err := os.Stat(b)
+	if err != nil {
+		return false
+	}
+
+	return os.SameFile(infoA, infoB)
+}
+
+// trimLap writes the first lap of the stub with the given number to w.
+func trimLap(w io.WriteSeeker, stub ibt.Stub, number int) error {
+	laps, err := ibt.SplitLaps(stub)
+	if err != nil {
+		return err
+	}
+
+	for _, lap := range laps {
+		if lap.Number == number {
+			return ibt.TrimLap(w, stub, lap)
+		}
+	}
+
+	return fmt.Errorf("lap %d not found in %s", number, stub.Filename())
+}
+
+// runSplit writes every lap of the given file to a new file in the output directory.
+func runSplit(args []string, stdout, stderr io.Writer) error {
+	flags, jsonOutput := newFlagSet("split", "-o directory file", stderr)
+	output := flags.String("o", ".", "directory to write the lap files to")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected a single file. received %d", flags.NArg())
+	}
+
+	stubs, err := ibt.ParseLazyStubs(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+
+	files, err := ibt.Split(stubs[0], *output)
+	if err != nil {
+		return err
+	}
+
+	if *jsonOutput {
+		return writeJSON(stdout, files)
+	}
+
+	for _, file := range files {
+		fmt.Fprintln(stdout, file)
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+
+	"github.com/teamjorge/ibt"
+	"github.com/teamjorge/ibt/headers"
+)
+
+// varInfo is the output of the vars command for a single variable.
+type varInfo struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Count       int    `json:"count"`
+	CountAsTime bool   `json:"count_as_time"`
+	Unit        string `json:"unit"`
+	Description string `json:"description"`
+}
+
+// runVars prints the variables of the given file, optionally filtered by a regular expression.
+func runVars(args []string, stdout, stderr io.Writer) error {
+	flags, jsonOutput := newFlagSet("vars", "file", stderr)
+	filter := flags.String("filter", "", "only include variables with a name matching the regular expression")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if flags.NArg() != 1 {
+		flags.Usage()
+		return fmt.Errorf("expected a single file. received %d", flags.NArg())
+	}
+
+	pattern, err := regexp.Compile(*filter)
+	if err != nil {
+		return fmt.Errorf("invalid filter %q: %v", *filter, err)
+	}
+
+	stubs, err := ibt.ParseLazyStubs(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer stubs.Close()
+
+	vars := filterVars(stubs[0].Headers().VarHeader, pattern)
+
+	if *jsonOutput {
+		return writeJSON(stdout, vars)
+	}
+
+	table := newTable(stdout)
+	fmt.Fprintln(table, "NAME\tTYPE\tCOUNT\tUNIT\tDESCRIPTION")
+	for _, v := range vars {
+		fmt.Fprintf(table, "%s\t%s\t%d\t%s\t%s\n", v.Name, v.Type, v.Count, v.Unit, v.Description)
+	}
+
+	return table.Flush()
+}
+
+// filterVars returns the variables with a name matching the pattern, sorted by name.
+func filterVars(varHeaders map[string]headers.VarHeader, pattern *regexp.Regexp) []varInfo {
+	vars := make([]varInfo, 0, len(varHeaders))
+
+	for name, vh := range varHeaders {
+		if !pattern.MatchString(name) {
+			continue
+		}
+
+		vars = append(vars, varInfo{
+			Name:        vh.Name,
+			Type:        rtypeName(vh.Rtype),
+			Count:       vh.Count,
+			CountAsTime: vh.CountAsTime,
+			Unit:        vh.Unit,
+			Description: vh.Description,
+		})
+	}
+
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+
+	return vars
+}
@@ -0,0 +1,325 @@
+package ibt
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+// Grid units of a Comparison
+const (
+	// Distance is given as a fraction of the lap, using LapDistPct
+	CompareByLapDistPct string = "pct"
+	// Distance is given in metres, using the TrackLength of the session
+	CompareByMetres string = "m"
+)
+
+// LapRange is a range of ticks within a stub that should be compared.
+type LapRange struct {
+	Stub Stub
+	// Index of the first tick of the range
+	Start int
+	// Index of the tick after the last tick of the range. A value of 0 will use the rest of the stub.
+	End int
+}
+
+// NewLapRange creates a LapRange for a lap created by SplitLaps.
+func NewLapRange(stub Stub, lap Lap) LapRange {
+	return LapRange{Stub: stub, Start: lap.Start, End: lap.End}
+}
+
+// CompareOptions configures the grid and channels used by CompareLaps.
+type CompareOptions struct {
+	// Variables to compare in addition to the time delta. Only scalar variables are supported.
+	Channels []string
+	// Grid unit, which is either CompareByLapDistPct (default) or CompareByMetres
+	Unit string
+	// Distance between grid points in the grid unit. Defaults to 0.001 for CompareByLapDistPct and 1 for CompareByMetres.
+	Resolution float64
+}
+
+// Comparison is the result of comparing two laps on a common distance grid.
+type Comparison struct {
+	// Grid unit, which is either CompareByLapDistPct or CompareByMetres
+	Unit string
+	// Distance of each grid point
+	Distance []float64
+	// Elapsed time of the first lap at each grid point
+	TimeA []float64
+	// Elapsed time of the second lap at each grid point
+	TimeB []float64
+	// Running time delta at each grid point. A positive delta means that the second lap is slower.
+	Delta []float64
+	// Compared channels by variable name
+	Channels map[string]ChannelComparison
+}
+
+// ChannelComparison is a single channel of both laps resampled onto the grid of a Comparison.
+type ChannelComparison struct {
+	Header headers.VarHeader
+	A      []float64
+	B      []float64
+	// Difference of the second lap compared to the first (B - A)
+	Diff []float64
+}
+
+// CompareLaps resamples two laps onto a common distance grid and compares them.
+//
+// The laps can be from different stubs. Only the distance covered by both laps is compared, which allows
+// incomplete laps to be compared as well. Elapsed time is measured from the start of this shared distance.
+// When using CompareByMetres, the TrackLength of the first lap's session is used.
+func CompareLaps(a, b LapRange, opts CompareOptions) (*Comparison, error) {
+	if opts.Unit == "" {
+		opts.Unit = CompareByLapDistPct
+	}
+
+	scale := 1.0
+	switch opts.Unit {
+	case CompareByLapDistPct:
+		if opts.Resolution <= 0 {
+			opts.Resolution = 0.001
+		}
+	case CompareByMetres:
+		if opts.Resolution <= 0 {
+			opts.Resolution = 1
+		}
+
+		trackLength, err := ParseTrackLength(a.Stub.header.SessionInfo.WeekendInfo.TrackLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compare laps of stub %s: %v", a.Stub.Filename(), err)
+		}
+		scale = trackLength
+	default:
+		return nil, fmt.Errorf("unknown comparison unit %s", opts.Unit)
+	}
+
+	lapA, err := loadCompareLap(a, scale, opts.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	lapB, err := loadCompareLap(b, scale, opts.Channels)
+	if err != nil {
+		return nil, err
+	}
+
+	return compareLaps(lapA, lapB, opts.Unit, opts.Resolution)
+}
+
+// ParseTrackLength parses the TrackLength of the WeekendInfo, such as "4.28 km", into metres.
+func ParseTrackLength(trackLength string) (float64, error) {
+	fields := strings.Fields(trackLength)
+	if len(fields) != 2 {
+		return 0, fmt.Errorf("invalid track length %q", trackLength)
+	}
+
+	length, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid track length %q: %v", trackLength, err)
+	}
+
+	switch fields[1] {
+	case "km":
+		return length * 1000, nil
+	case "mi":
+		return length * 1609.344, nil
+	case "m":
+		return length, nil
+	}
+
+	return 0, fmt.Errorf("unknown unit for track length %q", trackLength)
+}
+
+// compareLap is a single lap with distance increasing strictly and channels converted to float64.
+type compareLap struct {
+	distance []float64
+	time     []float64
+	channels map[string][]float64
+	headers  map[string]headers.VarHeader
+}
+
+// loadCompareLap loads the distance, time, and channels of the given range.
+//
+// LapDistPct wraps to 0 at the start/finish line, which is unwrapped to ensure the lap distance is
+// continuous. Ticks that do not move the car forward are discarded, as they can not be interpolated.
+func loadCompareLap(lap LapRange, scale float64, channels []string) (compareLap, error) {
+	vars := append([]string{"LapDistPct", "SessionTime"}, channels...)
+
+	frame, err := LoadChannelsRange(lap.Stub, lap.Start, lap.End, vars...)
+	if err != nil {
+		return compareLap{}, err
+	}
+
+	result := compareLap{
+		channels: make(map[string][]float64, len(channels)),
+		headers:  make(map[string]headers.VarHeader, len(channels)),
+	}
+
+	pct, err := columnFloat64(frame.Columns["LapDistPct"])
+	if err != nil {
+		return compareLap{}, err
+	}
+
+	sessionTime, err := columnFloat64(frame.Columns["SessionTime"])
+	if err != nil {
+		return compareLap{}, err
+	}
+
+	values := make(map[string][]float64, len(channels))
+	for _, name := range channels {
+		if values[name], err = columnFloat64(frame.Columns[name]); err != nil {
+			return compareLap{}, err
+		}
+		result.headers[name] = frame.Columns[name].Header
+	}
+
+	offset := 0.0
+	// Laps split by the Lap variable may start just before the start/finish line
+	if len(pct) > 0 && pct[0] > 0.5 {
+		offset = -1
+	}
+
+	last := -1.0
+	for i := range pct {
+		// LapDistPct is negative when the car is not on track
+		if pct[i] < 0 {
+			continue
+		}
+
+		if last >= 0 && last-pct[i] > 0.5 {
+			offset++
+		}
+		last = pct[i]
+
+		distance := (pct[i] + offset) * scale
+		if n := len(result.distance); n > 0 && distance <= result.distance[n-1] {
+			continue
+		}
+
+		result.distance = append(result.distance, distance)
+		result.time = append(result.time, sessionTime[i])
+		for name := range values {
+			result.channels[name] = append(result.channels[name], values[name][i])
+		}
+	}
+
+	return result, nil
+}
+
+// compareLaps resamples both laps onto a grid spanning their shared distance.
+func compareLaps(a, b compareLap, unit string, resolution float64) (*Comparison, error) {
+	if len(a.distance) < 2 || len(b.distance) < 2 {
+		return nil, errors.New("laps require at least two moving ticks to be compared")
+	}
+
+	start := math.Max(a.distance[0], b.distance[0])
+	end := math.Min(a.distance[len(a.distance)-1], b.distance[len(b.distance)-1])
+	if start >= end {
+		return nil, errors.New("laps do not cover any shared distance")
+	}
+
+	points := int(math.Floor((end-start)/resolution)) + 1
+
+	comparison := &Comparison{
+		Unit:     unit,
+		Distance: make([]float64, points),
+		TimeA:    make([]float64, points),
+		TimeB:    make([]float64, points),
+		Delta:    make([]float64, points),
+		Channels: make(map[string]ChannelComparison, len(a.channels)),
+	}
+
+	for i := range comparison.Distance {
+		comparison.Distance[i] = start + float64(i)*resolution
+	}
+
+	startA := interpolate(a.distance, a.time, start)
+	startB := interpolate(b.distance, b.time, start)
+
+	for i, distance := range comparison.Distance {
+		comparison.TimeA[i] = interpolate(a.distance, a.time, distance) - startA
+		comparison.TimeB[i] = interpolate(b.distance, b.time, distance) - startB
+		comparison.Delta[i] = comparison.TimeB[i] - comparison.TimeA[i]
+	}
+
+	for name := range a.channels {
+		channel := ChannelComparison{
+			Header: a.headers[name],
+			A:      make([]float64, points),
+			B:      make([]float64, points),
+			Diff:   make([]float64, points),
+		}
+
+		for i, distance := range comparison.Distance {
+			channel.A[i] = interpolate(a.distance, a.channels[name], distance)
+			channel.B[i] = interpolate(b.distance, b.channels[name], distance)
+			channel.Diff[i] = channel.B[i] - channel.A[i]
+		}
+
+		comparison.Channels[name] = channel
+	}
+
+	return comparison, nil
+}
+
+// interpolate the value at x using the strictly increasing xs and their values.
+//
+// Values outside of the range of xs are clamped to the first or last value.
+func interpolate(xs, values []float64, x float64) float64 {
+	i := sort.SearchFloat64s(xs, x)
+
+	if i == 0 {
+		return values[0]
+	}
+	if i == len(xs) {
+		return values[len(values)-1]
+	}
+
+	ratio := (x - xs[i-1]) / (xs[i] - xs[i-1])
+
+	return values[i-1] + ratio*(values[i]-values[i-1])
+}
+
+// columnFloat64 converts the values of a scalar column to float64.
+func columnFloat64(column *Column) ([]float64, error) {
+	if column == nil {
+		return nil, errors.New("column not loaded")
+	}
+
+	switch values := column.Values.(type) {
+	case []uint8:
+		return convertFloat64(values), nil
+	case []int:
+		return convertFloat64(values), nil
+	case []uint32:
+		return convertFloat64(values), nil
+	case []float32:
+		return convertFloat64(values), nil
+	case []float64:
+		return values, nil
+	case []bool:
+		result := make([]float64, len(values))
+		for i, value := range values {
+			if value {
+				result[i] = 1
+			}
+		}
+		return result, nil
+	}
+
+	return nil, fmt.Errorf("variable %s is not a scalar numeric variable", column.Header.Name)
+}
+
+func convertFloat64[T uint8 | int | uint32 | float32](values []T) []float64 {
+	result := make([]float64, len(values))
+	for i, value := range values {
+		result[i] = float64(value)
+	}
+
+	return result
+}
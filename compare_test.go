@@ -0,0 +1,139 @@
+package ibt
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCompareLaps(t *testing.T) {
+	stubs, err := ParseStubs(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to parse stubs for testing file - %v", err)
+		return
+	}
+	defer stubs.Close()
+
+	laps, err := SplitLaps(stubs[0])
+	if err != nil {
+		t.Errorf("failed to split laps for testing file - %v", err)
+		return
+	}
+
+	t.Run("test CompareLaps same lap", func(t *testing.T) {
+		lap := NewLapRange(stubs[0], laps[0])
+
+		comparison, err := CompareLaps(lap, lap, CompareOptions{Channels: []string{"Speed", "Gear"}, Resolution: 0.00001})
+		if err != nil {
+			t.Errorf("expected CompareLaps() to run without err. received error: %v", err)
+			return
+		}
+
+		if comparison.Unit != CompareByLapDistPct || len(comparison.Distance) == 0 {
+			t.Errorf("expected a LapDistPct grid. received %s with %d points", comparison.Unit, len(comparison.Distance))
+		}
+
+		for i := range comparison.Delta {
+			if comparison.Delta[i] != 0 || comparison.Channels["Speed"].Diff[i] != 0 {
+				t.Errorf("expected no differences when comparing a lap with itself. received delta %f at %d", comparison.Delta[i], i)
+				return
+			}
+		}
+
+		if comparison.Channels["Speed"].Header.Unit != "m/s" {
+			t.Errorf("expected Speed header to be included. received %+v", comparison.Channels["Speed"].Header)
+		}
+	})
+
+	t.Run("test CompareLaps metres", func(t *testing.T) {
+		lap := LapRange{Stub: stubs[0], Start: 0, End: 200}
+
+		comparison, err := CompareLaps(lap, lap, CompareOptions{Unit: CompareByMetres, Resolution: 0.1})
+		if err != nil {
+			t.Errorf("expected CompareLaps() to run without err. received error: %v", err)
+			return
+		}
+
+		if comparison.Unit != CompareByMetres || comparison.Distance[0] < 236 || comparison.Distance[0] > 237 {
+			t.Errorf("expected the grid to start at roughly 236 metres. received %v", comparison.Distance)
+		}
+	})
+
+	t.Run("test CompareLaps invalid options", func(t *testing.T) {
+		lap := NewLapRange(stubs[0], laps[0])
+
+		if _, err := CompareLaps(lap, lap, CompareOptions{Unit: "ft"}); err == nil {
+			t.Error("expected CompareLaps() to return an error for an unknown unit")
+		}
+
+		if _, err := CompareLaps(lap, lap, CompareOptions{Channels: []string{"SteeringWheelTorque_ST"}}); err == nil {
+			t.Error("expected CompareLaps() to return an error for an array variable")
+		}
+
+		if _, err := CompareLaps(lap, lap, CompareOptions{Channels: []string{"NotFound"}}); err == nil {
+			t.Error("expected CompareLaps() to return an error for a missing variable")
+		}
+	})
+}
+
+func TestCompareLapsDelta(t *testing.T) {
+	// Lap A runs at a constant 0.01 of the lap per second, whereas lap B loses a second in the second half of the lap
+	// and starts just before the start/finish line.
+	a := compareLap{
+		distance: []float64{0, 0.5, 1},
+		time:     []float64{100, 150, 200},
+		channels: map[string][]float64{"Speed": {10, 10, 10}},
+	}
+	b := compareLap{
+		distance: []float64{-0.01, 0.5, 1},
+		time:     []float64{9, 60, 111},
+		channels: map[string][]float64{"Speed": {8, 12, 8}},
+	}
+
+	comparison, err := compareLaps(a, b, CompareByLapDistPct, 0.25)
+	if err != nil {
+		t.Errorf("expected compareLaps() to run without err. received error: %v", err)
+		return
+	}
+
+	expectedDelta := []float64{0, 0, 0, 0.5, 1}
+	if len(comparison.Delta) != len(expectedDelta) {
+		t.Errorf("expected %d grid points. received %v", len(expectedDelta), comparison.Distance)
+		return
+	}
+
+	for i := range expectedDelta {
+		if math.Abs(comparison.Delta[i]-expectedDelta[i]) > 1e-9 {
+			t.Errorf("expected delta at %f to be %f. received %f", comparison.Distance[i], expectedDelta[i], comparison.Delta[i])
+		}
+	}
+
+	if speed := comparison.Channels["Speed"]; math.Abs(speed.Diff[2]-2) > 1e-9 || math.Abs(speed.Diff[4]+2) > 1e-9 {
+		t.Errorf("expected Speed differences of 2 and -2. received %v", speed.Diff)
+	}
+
+	if _, err := compareLaps(a, compareLap{distance: []float64{2, 3}, time: []float64{0, 1}}, CompareByLapDistPct, 0.25); err == nil {
+		t.Error("expected compareLaps() to return an error for laps without shared distance")
+	}
+}
+
+func TestParseTrackLength(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		err      bool
+	}{
+		{"4.28 km", 4280, false},
+		{"2.00 mi", 3218.688, false},
+		{"800 m", 800, false},
+		{"4.28", 0, true},
+		{"abc km", 0, true},
+		{"4.28 ft", 0, true},
+	}
+
+	for _, test := range tests {
+		result, err := ParseTrackLength(test.input)
+		if (err != nil) != test.err || math.Abs(result-test.expected) > 1e-9 {
+			t.Errorf("expected ParseTrackLength(%q) to return %f (error: %v). received %f and %v", test.input, test.expected, test.err, result, err)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package ibt
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ErrorPolicy determines how errors returned by processors are handled.
+//
+// With every policy other than ErrorPolicyAbort, the errors are recorded and returned as ProcessErrors once
+// the group has been processed. The policies only differ in how processing continues after an error.
+type ErrorPolicy int
+
+const (
+	// ErrorPolicyAbort stops processing at the first processor error and returns it. This is the default.
+	ErrorPolicyAbort ErrorPolicy = iota
+	// ErrorPolicySkipTick skips the tick for the remaining processors after an error.
+	// Processing continues with the next tick. The last tick of a stub is never skipped, as processors
+	// such as exporters rely on it to finish the stub.
+	ErrorPolicySkipTick
+	// ErrorPolicyDisableProcessor stops passing ticks to the failing processor for the rest of the group
+	// after an error. The other processors are not affected.
+	ErrorPolicyDisableProcessor
+	// ErrorPolicyCollect continues processing after an error with all processors.
+	ErrorPolicyCollect
+)
+
+// String is the name of the policy
+func (e ErrorPolicy) String() string {
+	switch e {
+	case ErrorPolicyAbort:
+		return "abort"
+	case ErrorPolicySkipTick:
+		return "skip tick"
+	case ErrorPolicyDisableProcessor:
+		return "disable processor"
+	case ErrorPolicyCollect:
+		return "collect"
+	}
+
+	return fmt.Sprintf("ErrorPolicy(%d)", int(e))
+}
+
+// ProcessError is an error returned by a processor, along with where it occurred.
+type ProcessError struct {
+	// Filename of the stub being processed
+	Filename string
+	// Index of the tick in the stub
+	Tick int
+	// Processor that returned the error
+	Processor Processor
+	// Error returned by the processor
+	Err error
+}
+
+func (e *ProcessError) Error() string {
+	return fmt.Sprintf("processor %T failed on %s at tick %d: %v", e.Processor, e.Filename, e.Tick, e.Err)
+}
+
+// Unwrap returns the error returned by the processor
+func (e *ProcessError) Unwrap() error { return e.Err }
+
+// ProcessErrors is the report of all processor errors recorded while processing a group.
+//
+// See ErrorPolicy for the policies that record errors instead of aborting.
+type ProcessErrors []*ProcessError
+
+func (e ProcessErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+
+	return fmt.Sprintf("%d processor errors occurred:\n%s", len(e), strings.Join(messages, "\n"))
+}
+
+// Unwrap returns the collected errors, allowing errors.Is and errors.As to match any of them
+func (e ProcessErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+
+	return errs
+}
+
+// Processor returns the errors returned by the given processor.
+//
+// Processors are matched by identity. Processors of types that can not be compared, such as structs with
+// slice or map fields passed by value, never match and should be passed as pointers instead.
+func (e ProcessErrors) Processor(proc Processor) ProcessErrors {
+	errs := make(ProcessErrors, 0)
+	for _, err := range e {
+		if sameProcessor(err.Processor, proc) {
+			errs = append(errs, err)
+		}
+	}
+
+	return errs
+}
+
+// sameProcessor compares the processors without panicking on types that can not be compared.
+func sameProcessor(a, b Processor) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.ValueOf(a).Comparable() {
+		return false
+	}
+
+	return a == b
+}
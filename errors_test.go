@@ -0,0 +1,68 @@
+package ibt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+// testValueProcessor is a processor with value receivers of a type that can not be compared
+type testValueProcessor struct {
+	whitelist []string
+}
+
+func (t testValueProcessor) Process(input Tick, hasNext bool, session *headers.Session) error {
+	return nil
+}
+
+func (t testValueProcessor) Whitelist() []string { return t.whitelist }
+
+func TestProcessErrors(t *testing.T) {
+	proc := &testErrorProcessor{}
+	cause := errors.New("unit test error")
+
+	report := ProcessErrors{
+		{Filename: "first.ibt", Tick: 5, Processor: proc, Err: cause},
+		{Filename: "second.ibt", Tick: 7, Processor: proc, Err: errors.New("other error")},
+	}
+
+	t.Run("test ProcessError Error()", func(t *testing.T) {
+		expected := "processor *ibt.testErrorProcessor failed on first.ibt at tick 5: unit test error"
+		if report[0].Error() != expected {
+			t.Errorf("expected error %q. received %q", expected, report[0].Error())
+		}
+	})
+
+	t.Run("test ProcessErrors Error()", func(t *testing.T) {
+		message := report.Error()
+		if !strings.HasPrefix(message, "2 processor errors occurred") || !strings.Contains(message, "second.ibt at tick 7") {
+			t.Errorf("expected all errors to be in the message. received %q", message)
+		}
+	})
+
+	t.Run("test ProcessErrors unwrap", func(t *testing.T) {
+		if !errors.Is(report, cause) {
+			t.Error("expected errors.Is to match a collected error")
+		}
+	})
+
+	t.Run("test ProcessErrors Processor()", func(t *testing.T) {
+		if len(report.Processor(proc)) != 2 || len(report.Processor(&testErrorProcessor{})) != 0 {
+			t.Errorf("expected errors to be matched by processor. received %v", report.Processor(proc))
+		}
+
+		// Processors that can not be compared should never match nor panic
+		uncomparable := append(report, &ProcessError{Processor: testValueProcessor{whitelist: []string{"Lap"}}, Err: cause})
+		if errs := uncomparable.Processor(testValueProcessor{whitelist: []string{"Lap"}}); len(errs) != 0 {
+			t.Errorf("expected no errors to match an uncomparable processor. received %v", errs)
+		}
+	})
+
+	t.Run("test ErrorPolicy String()", func(t *testing.T) {
+		if ErrorPolicyDisableProcessor.String() != "disable processor" || ErrorPolicy(10).String() != "ErrorPolicy(10)" {
+			t.Errorf("expected policy names. received %s and %s", ErrorPolicyDisableProcessor, ErrorPolicy(10))
+		}
+	})
+}
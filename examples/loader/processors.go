@@ -51,6 +51,22 @@ func (l *loaderProcessor) Process(input ibt.Tick, hasNext bool, session *headers
 	return nil
 }
 
+// FinishGroup is called once all the stubs of the group were processed.
+//
+// Any ticks that are still in the cache did not reach the threshold, so we load them as a final batch.
+func (l *loaderProcessor) FinishGroup(group ibt.StubGroup) error {
+	if len(l.cache) == 0 {
+		return nil
+	}
+
+	if err := l.loadBatch(); err != nil {
+		return fmt.Errorf("failed to load final batch - %v", err)
+	}
+	l.cache = make([]map[string]interface{}, 0)
+
+	return nil
+}
+
 func (l *loaderProcessor) loadBatch() error {
 	// Bulk load our batch to storage.
 	return l.Exec(l.cache)
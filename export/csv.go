@@ -0,0 +1,145 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/teamjorge/ibt"
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
+)
+
+// CSVOptions configures a CSVWriter.
+type CSVOptions struct {
+	// Delimiter used to separate values. Defaults to a comma.
+	Delimiter rune
+	// Filter for the exported ticks
+	Filter
+}
+
+// CSVWriter is a processor that writes telemetry ticks as delimited rows.
+//
+// The first row is a header with the name and unit of each variable, for example "Speed (m/s)". Array
+// variables are flattened into a column per index, for example "CarIdxLapDistPct[0] (%)".
+type CSVWriter struct {
+	w         *csv.Writer
+	fields    []field
+	whitelist []string
+	filter    *tickFilter
+
+	headerWritten bool
+}
+
+// NewCSVWriter creates a new CSVWriter for the given stubs and variable whitelist.
+//
+// The variables of the first stub are used to determine the columns. If no variables or a single value
+// of "*" is received, all variables will be exported.
+func NewCSVWriter(w io.Writer, stubs ibt.StubGroup, whitelist []string, opts CSVOptions) (*CSVWriter, error) {
+	fields, whitelist, err := resolveFields(stubs, whitelist)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &CSVWriter{
+		w:         csv.NewWriter(w),
+		fields:    fields,
+		whitelist: whitelist,
+		filter:    newTickFilter(opts.Filter),
+	}
+
+	if opts.Delimiter != 0 {
+		c.w.Comma = opts.Delimiter
+	}
+
+	return c, nil
+}
+
+// NewTSVWriter creates a new CSVWriter that separates values with tabs.
+func NewTSVWriter(w io.Writer, stubs ibt.StubGroup, whitelist []string, opts CSVOptions) (*CSVWriter, error) {
+	opts.Delimiter = '\t'
+
+	return NewCSVWriter(w, stubs, whitelist, opts)
+}
+
+// Process writes the given tick as a row.
+//
+// The writer is flushed once the last tick of a stub has been processed.
+func (c *CSVWriter) Process(input ibt.Tick, hasNext bool, session *headers.Session) error {
+	if !c.headerWritten {
+		if err := c.writeHeader(); err != nil {
+			return err
+		}
+	}
+
+	if c.filter.keep(input) {
+		row := make([]string, len(c.fields))
+		for i, f := range c.fields {
+			if value, ok := f.value(input); ok {
+				row[i] = formatValue(value)
+			}
+		}
+
+		if err := c.w.Write(row); err != nil {
+			return fmt.Errorf("failed to write csv row: %v", err)
+		}
+	}
+
+	if !hasNext {
+		return c.Flush()
+	}
+
+	return nil
+}
+
+// Whitelist of the variables required for the export
+func (c *CSVWriter) Whitelist() []string { return c.filter.whitelist(c.whitelist) }
+
+// Flush any buffered rows to the underlying writer.
+func (c *CSVWriter) Flush() error {
+	c.w.Flush()
+
+	return c.w.Error()
+}
+
+// writeHeader writes the column names and units as the first row
+func (c *CSVWriter) writeHeader() error {
+	row := make([]string, len(c.fields))
+	for i, f := range c.fields {
+		row[i] = f.Name()
+		if f.header.Unit != "" {
+			row[i] = fmt.Sprintf("%s (%s)", row[i], f.header.Unit)
+		}
+	}
+
+	if err := c.w.Write(row); err != nil {
+		return fmt.Errorf("failed to write csv header: %v", err)
+	}
+
+	c.headerWritten = true
+
+	return nil
+}
+
+// formatValue converts a single telemetry value to it's string representation
+func formatValue(value interface{}) string {
+	switch v := value.(type) {
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case uint8:
+		return strconv.Itoa(int(v))
+	case bool:
+		return strconv.FormatBool(v)
+	case string:
+		return v
+	case irsdk.BitField:
+		return fmt.Sprintf("0x%x", v.Raw())
+	}
+
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,124 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"github.com/teamjorge/ibt"
+)
+
+func TestCSVWriter(t *testing.T) {
+	stubs, err := ibt.ParseStubs("../.testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to parse stubs for testing file - %v", err)
+		return
+	}
+	defer stubs.Close()
+
+	t.Run("test CSVWriter header and rows", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, err := NewCSVWriter(buf, stubs, []string{"Speed", "Lap", "SteeringWheelTorque_ST"}, CSVOptions{})
+		if err != nil {
+			t.Errorf("expected NewCSVWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if err := ibt.Process(context.Background(), stubs, w); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+		}
+
+		rows, err := csv.NewReader(buf).ReadAll()
+		if err != nil {
+			t.Errorf("expected output to be valid csv. received error: %v", err)
+			return
+		}
+
+		expectedHeader := "Speed (m/s),Lap,SteeringWheelTorque_ST[0] (N*m),SteeringWheelTorque_ST[1] (N*m)"
+		if !strings.HasPrefix(strings.Join(rows[0], ","), expectedHeader) || len(rows[0]) != 8 {
+			t.Errorf("expected header to start with %s and have %d columns. received %v", expectedHeader, 8, rows[0])
+		}
+
+		if len(rows) != 391 {
+			t.Errorf("expected %d rows to be written. received %d", 391, len(rows))
+		}
+
+		if rows[1][1] != "9" {
+			t.Errorf("expected first Lap value to be %s. received %s", "9", rows[1][1])
+		}
+	})
+
+	t.Run("test TSVWriter decimation", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, err := NewTSVWriter(buf, stubs, []string{"Speed"}, CSVOptions{Filter: Filter{Decimation: 10}})
+		if err != nil {
+			t.Errorf("expected NewTSVWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if err := ibt.Process(context.Background(), stubs, w); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+		}
+
+		r := csv.NewReader(buf)
+		r.Comma = '\t'
+		rows, err := r.ReadAll()
+		if err != nil {
+			t.Errorf("expected output to be valid tsv. received error: %v", err)
+			return
+		}
+
+		if len(rows) != 40 {
+			t.Errorf("expected %d rows to be written. received %d", 40, len(rows))
+		}
+	})
+
+	t.Run("test CSVWriter lap filter", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, err := NewCSVWriter(buf, stubs, []string{"Speed"}, CSVOptions{Filter: Filter{Laps: []int{10}}})
+		if err != nil {
+			t.Errorf("expected NewCSVWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if w.Whitelist()[1] != "Lap" {
+			t.Errorf("expected Lap to be added to the whitelist. received %v", w.Whitelist())
+		}
+
+		if err := ibt.Process(context.Background(), stubs, w); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+		}
+
+		rows, _ := csv.NewReader(buf).ReadAll()
+		if len(rows) != 1 || len(rows[0]) != 1 {
+			t.Errorf("expected only the header to be written. received %v", rows)
+		}
+	})
+
+	t.Run("test NewCSVWriter invalid variables", func(t *testing.T) {
+		if _, err := NewCSVWriter(new(bytes.Buffer), stubs, []string{"NotFound"}, CSVOptions{}); err == nil {
+			t.Error("expected NewCSVWriter() to return an error for a missing variable")
+		}
+
+		if _, err := NewCSVWriter(new(bytes.Buffer), nil, []string{"Speed"}, CSVOptions{}); err == nil {
+			t.Error("expected NewCSVWriter() to return an error for no stubs")
+		}
+	})
+
+	t.Run("test CSVWriter all variables", func(t *testing.T) {
+		w, err := NewCSVWriter(new(bytes.Buffer), stubs, nil, CSVOptions{})
+		if err != nil {
+			t.Errorf("expected NewCSVWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if len(w.Whitelist()) != 276 || w.Whitelist()[0] != "SessionTime" {
+			t.Errorf("expected all variables ordered by offset to be exported. received %v", w.Whitelist())
+		}
+	})
+}
@@ -0,0 +1,154 @@
+// Package export provides processors for writing telemetry to external file formats.
+//
+// Exporters implement the ibt.Processor interface, which allows them to be composed with other processors
+// when using ibt.Process.
+//
+// # Formats
+//
+//   - CSVWriter - Comma (or tab) separated values with unit-annotated headers.
+//   - ParquetWriter - Apache Parquet files with session metadata, written without any external dependencies.
+//   - JSONLWriter - JSON Lines with a session metadata header line for every ibt file.
+//
+// MoTeC i2 log files require all samples of a channel upfront and are therefore written per stub
+// with WriteLD and WriteLDX instead of a processor.
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/teamjorge/ibt"
+	"github.com/teamjorge/ibt/headers"
+)
+
+// Filter determines which ticks are exported.
+type Filter struct {
+	// Decimation will only export every Nth tick. Values of 1 or less will export every tick.
+	Decimation int
+	// Laps to export. All laps will be exported when empty.
+	Laps []int
+}
+
+// tickFilter keeps track of the ticks seen by an exporter to apply a Filter.
+type tickFilter struct {
+	decimation int
+	laps       map[int]struct{}
+
+	index int
+}
+
+func newTickFilter(filter Filter) *tickFilter {
+	f := &tickFilter{decimation: filter.Decimation}
+
+	if len(filter.Laps) > 0 {
+		f.laps = make(map[int]struct{}, len(filter.Laps))
+		for _, lap := range filter.Laps {
+			f.laps[lap] = struct{}{}
+		}
+	}
+
+	return f
+}
+
+// whitelist adds the variables required for filtering to the given whitelist.
+func (f *tickFilter) whitelist(whitelist []string) []string {
+	if f.laps == nil {
+		return whitelist
+	}
+
+	return append(append(make([]string, 0, len(whitelist)+1), whitelist...), "Lap")
+}
+
+// keep determines if the given tick should be exported and advances the tick index.
+func (f *tickFilter) keep(tick ibt.Tick) bool {
+	index := f.index
+	f.index++
+
+	if f.decimation > 1 && index%f.decimation != 0 {
+		return false
+	}
+
+	if f.laps != nil {
+		lap, err := ibt.GetTickValue[int](tick, "Lap")
+		if err != nil {
+			return false
+		}
+
+		if _, ok := f.laps[lap]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// field is a single exported column. Array variables are flattened into a field per index.
+type field struct {
+	header headers.VarHeader
+	// Index of the item for array variables. -1 for non-array variables.
+	index int
+}
+
+// Name of the field, including the index for array variables. For example, CarIdxLapDistPct[3].
+func (f field) Name() string {
+	if f.index < 0 {
+		return f.header.Name
+	}
+
+	return fmt.Sprintf("%s[%d]", f.header.Name, f.index)
+}
+
+// value retrieves the value of the field from the given tick.
+func (f field) value(tick ibt.Tick) (interface{}, bool) {
+	value, ok := tick[f.header.Name]
+	if !ok || value == nil {
+		return nil, false
+	}
+
+	if f.index < 0 {
+		return value, true
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice || f.index >= rv.Len() {
+		return nil, false
+	}
+
+	return rv.Index(f.index).Interface(), true
+}
+
+// resolveFields determines the exported fields for the whitelist from the variables of the first stub.
+//
+// If no values or a single value of "*" is received, all variables will be exported in the order they appear in the telemetry buffer.
+func resolveFields(stubs ibt.StubGroup, whitelist []string) ([]field, []string, error) {
+	if len(stubs) == 0 {
+		return nil, nil, fmt.Errorf("no stubs received for export")
+	}
+
+	vars := stubs[0].Headers().VarHeader
+
+	if len(whitelist) == 0 || (len(whitelist) == 1 && whitelist[0] == "*") {
+		whitelist = headers.AvailableVars(vars)
+		sort.Slice(whitelist, func(i, j int) bool { return vars[whitelist[i]].Offset < vars[whitelist[j]].Offset })
+	}
+
+	fields := make([]field, 0, len(whitelist))
+	for _, name := range whitelist {
+		vh, ok := vars[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("variable %s not found in var headers of %s", name, stubs[0].Filename())
+		}
+
+		if vh.Count <= 1 {
+			fields = append(fields, field{header: vh, index: -1})
+			continue
+		}
+
+		for i := 0; i < vh.Count; i++ {
+			fields = append(fields, field{header: vh, index: i})
+		}
+	}
+
+	return fields, whitelist, nil
+}
@@ -0,0 +1,235 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+
+	"github.com/teamjorge/ibt"
+	"github.com/teamjorge/ibt/headers"
+)
+
+// JSONLOptions configures a JSONLWriter.
+type JSONLOptions struct {
+	// Filter for the exported ticks
+	Filter
+}
+
+// JSONLHeader is the first line written for every stub by a JSONLWriter.
+type JSONLHeader struct {
+	// Base name of the ibt file
+	File            string                   `json:"file"`
+	TelemetryHeader *headers.TelemetryHeader `json:"telemetry_header"`
+	DiskHeader      *headers.DiskHeader      `json:"disk_header"`
+	WeekendInfo     headers.WeekendInfo      `json:"weekend_info"`
+	DriverInfo      headers.DriverInfo       `json:"driver_info"`
+	// Exported variables, sorted by name
+	Vars []headers.VarHeader `json:"vars"`
+}
+
+// JSONLTick is a single tick line written by a JSONLWriter.
+//
+// Fields are declared in the order of their keys to keep the keys of the line sorted.
+type JSONLTick struct {
+	// Session time derived from the StartTime of the file and the TickRate, or the SessionTime variable
+	SessionTime float64 `json:"session_time"`
+	// Index of the tick within the ibt file
+	Tick int `json:"tick"`
+	// Exported variable values. NaN and infinite values are written as null and bitfields as their raw value.
+	Values map[string]interface{} `json:"values"`
+}
+
+// JSONLWriter is a processor that writes telemetry as JSON Lines (NDJSON).
+//
+// Each stub starts with a JSONLHeader line, followed by a JSONLTick line per tick. Object keys are
+// sorted, which makes the output deterministic and usable as a golden file format.
+//
+// The writer relies on the StartStub hook of ibt.Process to know which stub the ticks belong to. When no
+// stub was started, such as with ibt.ProcessMerged, header lines are not written and the session time of
+// each tick is taken from it's SessionTime variable.
+type JSONLWriter struct {
+	w         *bufio.Writer
+	enc       *json.Encoder
+	whitelist []string
+	filter    *tickFilter
+
+	// Stub that is currently being written and the record index of the next tick
+	stub          ibt.Stub
+	tick          int
+	headerWritten bool
+}
+
+// NewJSONLWriter creates a new JSONLWriter for the given stubs and variable whitelist.
+//
+// If no variables or a single value of "*" is received, all variables will be exported.
+func NewJSONLWriter(w io.Writer, stubs ibt.StubGroup, whitelist []string, opts JSONLOptions) (*JSONLWriter, error) {
+	_, whitelist, err := resolveFields(stubs, whitelist)
+	if err != nil {
+		return nil, err
+	}
+
+	bw := bufio.NewWriter(w)
+
+	return &JSONLWriter{
+		w:         bw,
+		enc:       json.NewEncoder(bw),
+		whitelist: whitelist,
+		filter:    newTickFilter(opts.Filter),
+	}, nil
+}
+
+// StartStub prepares the writer for the first tick of the stub.
+func (j *JSONLWriter) StartStub(stub ibt.Stub) error {
+	j.stub = stub
+	j.tick = 0
+	j.headerWritten = false
+
+	return nil
+}
+
+// Process writes the given tick as a line.
+//
+// The header line of a stub is written before it's first tick. The writer is flushed once the last
+// tick of a stub has been processed.
+func (j *JSONLWriter) Process(input ibt.Tick, hasNext bool, session *headers.Session) error {
+	stub := j.stub
+
+	if !j.headerWritten && stub.Headers() != nil {
+		if err := j.writeHeader(stub); err != nil {
+			return err
+		}
+	}
+
+	if j.filter.keep(input) {
+		line := JSONLTick{
+			Tick:        j.tick,
+			SessionTime: j.sessionTime(input),
+			Values:      make(map[string]interface{}, len(j.whitelist)),
+		}
+
+		for _, name := range j.whitelist {
+			line.Values[name] = JSONValue(input[name])
+		}
+
+		if err := j.enc.Encode(line); err != nil {
+			return fmt.Errorf("failed to write tick %d of %s: %v", j.tick, stub.Filename(), err)
+		}
+	}
+
+	j.tick++
+
+	if !hasNext {
+		return j.Flush()
+	}
+
+	return nil
+}
+
+// Whitelist of the variables required for the export, including SessionTime for when no stub was started.
+func (j *JSONLWriter) Whitelist() []string {
+	whitelist := j.filter.whitelist(j.whitelist)
+
+	return append(append(make([]string, 0, len(whitelist)+1), whitelist...), "SessionTime")
+}
+
+// Flush any buffered lines to the underlying writer.
+func (j *JSONLWriter) Flush() error { return j.w.Flush() }
+
+// writeHeader writes the header line of the given stub
+func (j *JSONLWriter) writeHeader(stub ibt.Stub) error {
+	header := stub.Headers()
+
+	line := JSONLHeader{
+		File:            filepath.Base(stub.Filename()),
+		TelemetryHeader: header.TelemetryHeader,
+		DiskHeader:      header.DiskHeader,
+		WeekendInfo:     header.SessionInfo.WeekendInfo,
+		DriverInfo:      header.SessionInfo.DriverInfo,
+		Vars:            make([]headers.VarHeader, 0, len(j.whitelist)),
+	}
+
+	for _, name := range j.whitelist {
+		if vh, ok := header.VarHeader[name]; ok {
+			vh.Value = nil
+			line.Vars = append(line.Vars, vh)
+		}
+	}
+	sort.Slice(line.Vars, func(i, k int) bool { return line.Vars[i].Name < line.Vars[k].Name })
+
+	if err := j.encodeSorted(line); err != nil {
+		return fmt.Errorf("failed to write header of %s: %v", stub.Filename(), err)
+	}
+
+	j.headerWritten = true
+
+	return nil
+}
+
+// sessionTime returns the session time of the tick.
+//
+// The session time is derived from the StartTime and TickRate of the current stub, falling back to the
+// SessionTime variable of the tick when no stub was started or the stub does not have a TickRate.
+func (j *JSONLWriter) sessionTime(input ibt.Tick) float64 {
+	header := j.stub.Headers()
+	if header != nil && header.DiskHeader != nil && header.TelemetryHeader != nil && header.TelemetryHeader.TickRate > 0 {
+		return header.DiskHeader.StartTime + float64(j.tick)/float64(header.TelemetryHeader.TickRate)
+	}
+
+	sessionTime, _ := input["SessionTime"].(float64)
+
+	return sessionTime
+}
+
+// encodeSorted writes the value as a line with the keys of all objects sorted.
+//
+// Struct fields are encoded in the order of their declaration, meaning the headers and session info
+// are decoded into maps first. Numbers are kept as they were encoded.
+func (j *JSONLWriter) encodeSorted(value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var sorted interface{}
+	if err := dec.Decode(&sorted); err != nil {
+		return err
+	}
+
+	return j.enc.Encode(sorted)
+}
+
+// JSONValue replaces NaN and infinite floats with nil, as they can not be represented in JSON.
+func JSONValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return nil
+		}
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return nil
+		}
+	case []float32:
+		values := make([]interface{}, len(v))
+		for i := range v {
+			values[i] = JSONValue(v[i])
+		}
+		return values
+	case []float64:
+		values := make([]interface{}, len(v))
+		for i := range v {
+			values[i] = JSONValue(v[i])
+		}
+		return values
+	}
+
+	return value
+}
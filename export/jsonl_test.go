@@ -0,0 +1,241 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"math"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/teamjorge/ibt"
+)
+
+var update = flag.Bool("update", false, "update golden files")
+
+func TestJSONLWriter(t *testing.T) {
+	stubs, err := ibt.ParseStubs("../.testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to parse stubs for testing file - %v", err)
+		return
+	}
+	defer stubs.Close()
+
+	t.Run("test JSONLWriter golden file", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, err := NewJSONLWriter(buf, stubs, []string{"Speed", "Lap", "SessionFlags", "SteeringWheelTorque_ST"}, JSONLOptions{Filter: Filter{Decimation: 60}})
+		if err != nil {
+			t.Errorf("expected NewJSONLWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if err := ibt.Process(context.Background(), stubs, w); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+		}
+
+		golden := "testdata/valid_test_file.jsonl"
+		if *update {
+			if err := os.WriteFile(golden, buf.Bytes(), 0644); err != nil {
+				t.Errorf("failed to update golden file - %v", err)
+			}
+		}
+
+		expected, err := os.ReadFile(golden)
+		if err != nil {
+			t.Errorf("failed to read golden file - %v", err)
+			return
+		}
+
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("expected output to match golden file %s", golden)
+		}
+	})
+
+	t.Run("test JSONLWriter envelope", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, err := NewJSONLWriter(buf, stubs, []string{"Speed"}, JSONLOptions{})
+		if err != nil {
+			t.Errorf("expected NewJSONLWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if err := ibt.Process(context.Background(), stubs, w); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+		}
+
+		scanner := bufio.NewScanner(buf)
+		scanner.Buffer(make([]byte, 0, 1024*1024), 1024*1024)
+
+		scanner.Scan()
+		var header JSONLHeader
+		if err := json.Unmarshal(scanner.Bytes(), &header); err != nil {
+			t.Errorf("expected header line to be valid json. received error: %v", err)
+		}
+
+		if header.File != "valid_test_file.ibt" || header.WeekendInfo.TrackName != "spielberg gp" || header.DiskHeader.RecordCount != 390 {
+			t.Errorf("expected header to describe the testing file. received %+v", header)
+		}
+
+		lines := 0
+		var tick JSONLTick
+		for scanner.Scan() {
+			if err := json.Unmarshal(scanner.Bytes(), &tick); err != nil {
+				t.Errorf("expected tick line to be valid json. received error: %v", err)
+			}
+			lines++
+		}
+
+		if lines != 390 {
+			t.Errorf("expected %d tick lines. received %d", 390, lines)
+		}
+
+		if tick.Tick != 389 || math.Abs(tick.SessionTime-938.4833339685914) > 0.0001 {
+			t.Errorf("expected last tick to be %d at %f. received %d at %f", 389, 938.4833339685914, tick.Tick, tick.SessionTime)
+		}
+	})
+
+	t.Run("test JSONLWriter sorted keys", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, err := NewJSONLWriter(buf, stubs, []string{"Speed", "Lap"}, JSONLOptions{Filter: Filter{Decimation: 100}})
+		if err != nil {
+			t.Errorf("expected NewJSONLWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if err := ibt.Process(context.Background(), stubs, w); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+		}
+
+		for idx, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+			dec := json.NewDecoder(bytes.NewReader(line))
+			dec.UseNumber()
+
+			var value interface{}
+			if err := dec.Decode(&value); err != nil {
+				t.Errorf("expected line %d to be valid json. received error: %v", idx, err)
+				continue
+			}
+
+			// Maps are encoded with sorted keys, meaning a sorted line is unchanged by a round trip
+			sorted, _ := json.Marshal(value)
+			if !bytes.Equal(line, sorted) {
+				t.Errorf("expected the keys of line %d to be sorted", idx)
+			}
+
+			if bytes.Contains(line, []byte(`"value":`)) {
+				t.Errorf("expected line %d to not contain variable values", idx)
+			}
+		}
+	})
+
+	t.Run("test JSONLWriter multiple stubs", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, err := NewJSONLWriter(buf, stubs, []string{"Lap"}, JSONLOptions{Filter: Filter{Decimation: 130}})
+		if err != nil {
+			t.Errorf("expected NewJSONLWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if err := ibt.Process(context.Background(), ibt.StubGroup{stubs[0], stubs[0]}, w); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+		}
+
+		ticks := make([]int, 0)
+		headers := 0
+		for _, line := range bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n")) {
+			var tick JSONLTick
+			if bytes.HasPrefix(line, []byte(`{"disk_header"`)) {
+				headers++
+				continue
+			}
+			if err := json.Unmarshal(line, &tick); err != nil {
+				t.Errorf("expected tick line to be valid json. received error: %v", err)
+			}
+			ticks = append(ticks, tick.Tick)
+		}
+
+		expected := []int{0, 130, 260, 0, 130, 260}
+		if headers != 2 || !reflect.DeepEqual(ticks, expected) {
+			t.Errorf("expected %d headers and ticks %v. received %d headers and ticks %v", 2, expected, headers, ticks)
+		}
+	})
+
+	t.Run("test JSONLWriter without StartStub", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, _ := NewJSONLWriter(buf, stubs, []string{"Lap"}, JSONLOptions{})
+		if err := w.Process(ibt.Tick{"Lap": 1, "SessionTime": 932.5}, false, nil); err != nil {
+			t.Errorf("expected Process() to run without err when no stub was started. received error: %v", err)
+		}
+
+		var tick JSONLTick
+		if err := json.Unmarshal(buf.Bytes(), &tick); err != nil {
+			t.Errorf("expected a single tick line to be written. received error: %v", err)
+		}
+
+		if tick.SessionTime != 932.5 {
+			t.Errorf("expected session time to be %f. received %f", 932.5, tick.SessionTime)
+		}
+	})
+
+	t.Run("test JSONLWriter ProcessMerged", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, err := NewJSONLWriter(buf, stubs, []string{"Lap"}, JSONLOptions{})
+		if err != nil {
+			t.Errorf("expected NewJSONLWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if err := ibt.ProcessMerged(context.Background(), stubs, w); err != nil {
+			t.Errorf("expected ProcessMerged() to run without err. received error: %v", err)
+		}
+
+		lines := bytes.Split(bytes.TrimSpace(buf.Bytes()), []byte("\n"))
+		if len(lines) != 390 {
+			t.Errorf("expected %d tick lines. received %d", 390, len(lines))
+			return
+		}
+
+		var tick JSONLTick
+		if err := json.Unmarshal(lines[len(lines)-1], &tick); err != nil {
+			t.Errorf("expected tick line to be valid json. received error: %v", err)
+		}
+
+		if tick.Tick != 389 || math.Abs(tick.SessionTime-938.4833339685914) > 0.0001 {
+			t.Errorf("expected last tick to be %d at %f. received %d at %f", 389, 938.4833339685914, tick.Tick, tick.SessionTime)
+		}
+	})
+
+	t.Run("test JSONLWriter without TickRate", func(t *testing.T) {
+		telemetry := stubs[0].Headers().TelemetryHeader
+		tickRate := telemetry.TickRate
+		telemetry.TickRate = 0
+		defer func() { telemetry.TickRate = tickRate }()
+
+		w, _ := NewJSONLWriter(new(bytes.Buffer), stubs, []string{"Lap"}, JSONLOptions{})
+		w.StartStub(stubs[0])
+
+		if sessionTime := w.sessionTime(ibt.Tick{"SessionTime": 932.5}); sessionTime != 932.5 {
+			t.Errorf("expected session time to be %f. received %f", 932.5, sessionTime)
+		}
+	})
+
+	t.Run("test JSONValue non-finite values", func(t *testing.T) {
+		if JSONValue(float32(math.NaN())) != nil || JSONValue(math.Inf(1)) != nil {
+			t.Error("expected non-finite values to be nil")
+		}
+
+		values := JSONValue([]float32{1, float32(math.NaN())}).([]interface{})
+		if values[0] != float32(1) || values[1] != nil {
+			t.Errorf("expected non-finite array values to be nil. received %v", values)
+		}
+	})
+}
@@ -0,0 +1,384 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/teamjorge/ibt"
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/metric"
+)
+
+// Sizes of the MoTeC ld file blocks
+const (
+	ldHeadSize    int = 1762
+	ldEventSize   int = 1154
+	ldVenueSize   int = 1100
+	ldVehicleSize int = 260
+	ldChannelSize int = 124
+)
+
+// MoTeC ld data types
+const (
+	ldIntType   uint16 = 0x05
+	ldFloatType uint16 = 0x07
+)
+
+// WriteLD writes the given variables of the stub as a MoTeC i2 log (.ld) file.
+//
+// Every variable is written as a channel with it's unit at the TickRate of the stub. Array variables are
+// flattened into a channel per index, with the exception of time based arrays (such as the 360Hz _ST
+// variables), which are written as a single channel at a higher frequency. Event, venue, vehicle, and
+// driver details are taken from the session info of the stub.
+//
+// If no variables or a single value of "*" is received, all variables will be written.
+func WriteLD(w io.Writer, stub ibt.Stub, vars ...string) error {
+	frame, err := ibt.LoadChannels(stub, vars...)
+	if err != nil {
+		return err
+	}
+
+	header := stub.Headers()
+	session := header.SessionInfo
+
+	channels := ldChannels(frame, header.TelemetryHeader.TickRate)
+
+	eventPtr := ldHeadSize
+	venuePtr := eventPtr + ldEventSize
+	vehiclePtr := venuePtr + ldVenueSize
+	metaPtr := vehiclePtr + ldVehicleSize
+	dataPtr := metaPtr + len(channels)*ldChannelSize
+
+	var driverName, carName, carPath string
+	if driver := session.GetDriver(); driver != nil {
+		driverName, carName, carPath = driver.UserName, driver.CarScreenName, driver.CarPath
+	}
+
+	var sessionName string
+	if len(session.SessionInfo.Sessions) > 0 {
+		sessionName = session.SessionInfo.Sessions[len(session.SessionInfo.Sessions)-1].SessionName
+	}
+
+	buf := new(bytes.Buffer)
+
+	// Header
+	writeLDValues(buf, uint32(0x40), make([]byte, 4), uint32(metaPtr), uint32(dataPtr), make([]byte, 20), uint32(eventPtr), make([]byte, 24),
+		uint16(1), uint16(0x4240), uint16(0xf), uint32(0x1f44), ldString("ADL", 8), uint16(420), uint16(0xadb0), uint32(len(channels)), make([]byte, 4),
+		ldString(stub.Time().Format("02/01/2006"), 16), make([]byte, 16), ldString(stub.Time().Format("15:04:05"), 16), make([]byte, 16),
+		ldString(driverName, 64), ldString(carName, 64), make([]byte, 64), ldString(session.WeekendInfo.TrackDisplayName, 64), make([]byte, 64),
+		make([]byte, 1024), uint32(0xc81a4), make([]byte, 66), ldString(session.WeekendInfo.TrackConfigName, 64), make([]byte, 126))
+
+	// Event
+	writeLDValues(buf, ldString(session.WeekendInfo.EventType, 64), ldString(sessionName, 64), ldString(session.WeekendInfo.TrackConfigName, 1024), uint16(venuePtr))
+
+	// Venue
+	writeLDValues(buf, ldString(session.WeekendInfo.TrackDisplayName, 64), make([]byte, 1034), uint16(vehiclePtr))
+
+	// Vehicle
+	writeLDValues(buf, ldString(carName, 64), make([]byte, 128), uint32(0), ldString(session.WeekendInfo.Category, 32), ldString(carPath, 32))
+
+	// Channel metadata, which is a doubly-linked list of channels
+	channelDataPtr := dataPtr
+	for i, channel := range channels {
+		prevPtr, nextPtr := 0, 0
+		if i > 0 {
+			prevPtr = metaPtr + (i-1)*ldChannelSize
+		}
+		if i < len(channels)-1 {
+			nextPtr = metaPtr + (i+1)*ldChannelSize
+		}
+
+		writeLDValues(buf, uint32(prevPtr), uint32(nextPtr), uint32(channelDataPtr), uint32(channel.len()), uint16(0x2ee1+i),
+			channel.dtype(), uint16(4), uint16(channel.freq), int16(0), int16(1), int16(1), int16(0),
+			ldString(channel.name, 32), ldString(channel.shortName(), 8), ldString(channel.unit, 12), make([]byte, 40))
+
+		channelDataPtr += channel.len() * 4
+	}
+
+	// Channel data
+	for _, channel := range channels {
+		if channel.floats != nil {
+			writeLDValues(buf, channel.floats)
+		} else {
+			writeLDValues(buf, channel.ints)
+		}
+	}
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write ld file for %s: %v", stub.Filename(), err)
+	}
+
+	return nil
+}
+
+// WriteLDX writes the MoTeC i2 extension (.ldx) file for the stub, containing a beacon for every lap.
+//
+// Beacons are derived from changes to the Lap variable. The total laps, fastest lap and fastest time
+// details are calculated from the laps that were completed in the stub.
+func WriteLDX(w io.Writer, stub ibt.Stub) error {
+	frame, err := ibt.LoadChannels(stub, "Lap")
+	if err != nil {
+		return err
+	}
+
+	laps, err := ibt.GetColumn[[]int](frame, "Lap")
+	if err != nil {
+		return err
+	}
+
+	tickRate := float64(stub.Headers().TelemetryHeader.TickRate)
+
+	ldx := ldxFile{Locale: "English_United Kingdom.1252", DefaultLocale: "C", Version: "1.6"}
+	ldx.Layers.Layer.MarkerBlock.MarkerGroup = ldxMarkerGroup{Name: "Beacons", Index: 3}
+
+	beacons := make([]float64, 0)
+	for i := 1; i < len(laps); i++ {
+		if laps[i] == laps[i-1] {
+			continue
+		}
+
+		beacon := float64(i) / tickRate
+		beacons = append(beacons, beacon)
+
+		ldx.Layers.Layer.MarkerBlock.MarkerGroup.Markers = append(ldx.Layers.Layer.MarkerBlock.MarkerGroup.Markers, ldxMarker{
+			Version:   100,
+			ClassName: "BCN",
+			Name:      fmt.Sprintf("Manual.%d", len(beacons)),
+			Flags:     77,
+			Time:      fmt.Sprintf("%.6fe+06", beacon),
+		})
+	}
+
+	fastestLap, fastestTime := 0, metric.LapTime(0)
+	for i := 1; i < len(beacons); i++ {
+		lapTime := metric.LapTime(beacons[i] - beacons[i-1])
+		if fastestLap == 0 || lapTime < fastestTime {
+			fastestLap, fastestTime = i, lapTime
+		}
+	}
+
+	ldx.Layers.Details = []ldxString{{"Total Laps", fmt.Sprint(len(beacons) + 1)}}
+	if fastestLap > 0 {
+		ldx.Layers.Details = append(ldx.Layers.Details,
+			ldxString{"Fastest Time", strings.TrimPrefix(fastestTime.ToString(), "0")},
+			ldxString{"Fastest Lap", fmt.Sprint(fastestLap)},
+		)
+	}
+
+	out, err := xml.MarshalIndent(ldx, "", " ")
+	if err != nil {
+		return fmt.Errorf("failed to encode ldx file for %s: %v", stub.Filename(), err)
+	}
+
+	if _, err := w.Write(append([]byte(xml.Header), out...)); err != nil {
+		return fmt.Errorf("failed to write ldx file for %s: %v", stub.Filename(), err)
+	}
+
+	return nil
+}
+
+// ldChannel is a single channel of a MoTeC ld file. Values are stored as either int32 or float32.
+type ldChannel struct {
+	name string
+	unit string
+	freq int
+
+	ints   []int32
+	floats []float32
+}
+
+func (c ldChannel) len() int {
+	if c.floats != nil {
+		return len(c.floats)
+	}
+
+	return len(c.ints)
+}
+
+func (c ldChannel) dtype() uint16 {
+	if c.floats != nil {
+		return ldFloatType
+	}
+
+	return ldIntType
+}
+
+// shortName removes any characters that do not fit the short name of a channel
+func (c ldChannel) shortName() string {
+	if len(c.name) > 8 {
+		return c.name[:8]
+	}
+
+	return c.name
+}
+
+// ldChannels converts the columns of the frame to channels, ordered by name.
+func ldChannels(frame *ibt.Frame, tickRate int) []ldChannel {
+	names := make([]string, 0, len(frame.Columns))
+	for name := range frame.Columns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	channels := make([]ldChannel, 0, len(names))
+	for _, name := range names {
+		channels = append(channels, ldColumnChannels(frame.Columns[name], tickRate)...)
+	}
+
+	return channels
+}
+
+// ldColumnChannels converts a single column to one or more channels.
+func ldColumnChannels(column *ibt.Column, tickRate int) []ldChannel {
+	vh := column.Header
+
+	switch values := column.Values.(type) {
+	case []uint8:
+		return []ldChannel{ldIntChannel(vh, tickRate, values)}
+	case []bool:
+		return []ldChannel{ldIntChannel(vh, tickRate, values)}
+	case []int:
+		return []ldChannel{ldIntChannel(vh, tickRate, values)}
+	case []uint32:
+		return []ldChannel{ldIntChannel(vh, tickRate, values)}
+	case []float32:
+		return []ldChannel{ldFloatChannel(vh, tickRate, values)}
+	case []float64:
+		return []ldChannel{ldFloatChannel(vh, tickRate, values)}
+	case [][]uint8:
+		return ldArrayChannels(vh, tickRate, values, ldIntChannel[uint8])
+	case [][]bool:
+		return ldArrayChannels(vh, tickRate, values, ldIntChannel[bool])
+	case [][]int:
+		return ldArrayChannels(vh, tickRate, values, ldIntChannel[int])
+	case [][]uint32:
+		return ldArrayChannels(vh, tickRate, values, ldIntChannel[uint32])
+	case [][]float32:
+		return ldArrayChannels(vh, tickRate, values, ldFloatChannel[float32])
+	case [][]float64:
+		return ldArrayChannels(vh, tickRate, values, ldFloatChannel[float64])
+	}
+
+	return nil
+}
+
+// ldArrayChannels flattens array values. Time based arrays become a single channel at a higher frequency,
+// whereas other arrays become a channel per index.
+func ldArrayChannels[T ibt.AccessorValueType](vh headers.VarHeader, tickRate int, values [][]T, channel func(headers.VarHeader, int, []T) ldChannel) []ldChannel {
+	if vh.CountAsTime {
+		flattened := make([]T, 0, len(values)*vh.Count)
+		for _, row := range values {
+			flattened = append(flattened, row...)
+		}
+
+		return []ldChannel{channel(vh, tickRate*vh.Count, flattened)}
+	}
+
+	channels := make([]ldChannel, 0, vh.Count)
+	for i := 0; i < vh.Count; i++ {
+		indexed := make([]T, len(values))
+		for row := range values {
+			indexed[row] = values[row][i]
+		}
+
+		indexedHeader := vh
+		indexedHeader.Name = fmt.Sprintf("%s_%d", vh.Name, i)
+
+		channels = append(channels, channel(indexedHeader, tickRate, indexed))
+	}
+
+	return channels
+}
+
+func ldIntChannel[T uint8 | bool | int | uint32](vh headers.VarHeader, freq int, values []T) ldChannel {
+	ints := make([]int32, len(values))
+	for i, value := range values {
+		switch v := any(value).(type) {
+		case bool:
+			if v {
+				ints[i] = 1
+			}
+		case uint8:
+			ints[i] = int32(v)
+		case int:
+			ints[i] = int32(v)
+		case uint32:
+			ints[i] = int32(v)
+		}
+	}
+
+	return ldChannel{name: vh.Name, unit: vh.Unit, freq: freq, ints: ints}
+}
+
+func ldFloatChannel[T float32 | float64](vh headers.VarHeader, freq int, values []T) ldChannel {
+	floats := make([]float32, len(values))
+	for i, value := range values {
+		floats[i] = float32(value)
+	}
+
+	return ldChannel{name: vh.Name, unit: vh.Unit, freq: freq, floats: floats}
+}
+
+// ldString creates a fixed size, zero padded string field
+func ldString(value string, size int) []byte {
+	field := make([]byte, size)
+	copy(field, strings.Map(func(r rune) rune {
+		if r > math.MaxInt8 {
+			return '?'
+		}
+		return r
+	}, value))
+
+	return field
+}
+
+// writeLDValues writes each of the values in little endian byte order
+func writeLDValues(buf *bytes.Buffer, values ...interface{}) {
+	for _, value := range values {
+		// Writing to a bytes.Buffer will not fail for fixed size values
+		_ = binary.Write(buf, binary.LittleEndian, value)
+	}
+}
+
+type ldxFile struct {
+	XMLName       xml.Name  `xml:"LDXFile"`
+	Locale        string    `xml:"Locale,attr"`
+	DefaultLocale string    `xml:"DefaultLocale,attr"`
+	Version       string    `xml:"Version,attr"`
+	Layers        ldxLayers `xml:"Layers"`
+}
+
+type ldxLayers struct {
+	Layer struct {
+		MarkerBlock struct {
+			MarkerGroup ldxMarkerGroup `xml:"MarkerGroup"`
+		} `xml:"MarkerBlock"`
+		RangeBlock struct{} `xml:"RangeBlock"`
+	} `xml:"Layer"`
+	Details []ldxString `xml:"Details>String"`
+}
+
+type ldxMarkerGroup struct {
+	Name    string      `xml:"Name,attr"`
+	Index   int         `xml:"Index,attr"`
+	Markers []ldxMarker `xml:"Marker"`
+}
+
+type ldxMarker struct {
+	Version   int    `xml:"Version,attr"`
+	ClassName string `xml:"ClassName,attr"`
+	Name      string `xml:"Name,attr"`
+	Flags     int    `xml:"Flags,attr"`
+	Time      string `xml:"Time,attr"`
+}
+
+type ldxString struct {
+	Id    string `xml:"Id,attr"`
+	Value string `xml:"Value,attr"`
+}
@@ -0,0 +1,127 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/xml"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/teamjorge/ibt"
+)
+
+func TestWriteLD(t *testing.T) {
+	stubs, err := ibt.ParseStubs("../.testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to parse stubs for testing file - %v", err)
+		return
+	}
+	defer stubs.Close()
+
+	buf := new(bytes.Buffer)
+	if err := WriteLD(buf, stubs[0], "Speed", "Lap", "SteeringWheelTorque_ST"); err != nil {
+		t.Errorf("expected WriteLD() to run without err. received error: %v", err)
+		return
+	}
+
+	out := buf.Bytes()
+	le := binary.LittleEndian
+	ldText := func(start, size int) string { return strings.TrimRight(string(out[start:start+size]), "\x00") }
+
+	if le.Uint32(out[0:4]) != 0x40 {
+		t.Errorf("expected ld marker to be %d. received %d", 0x40, le.Uint32(out[0:4]))
+	}
+
+	if n := le.Uint32(out[86:90]); n != 3 {
+		t.Errorf("expected %d channels. received %d", 3, n)
+	}
+
+	if driver := ldText(158, 64); driver != "George v Rensburg" {
+		t.Errorf("expected driver to be %s. received %s", "George v Rensburg", driver)
+	}
+
+	eventPtr := int(le.Uint32(out[36:40]))
+	if eventType := ldText(eventPtr, 64); eventType != "Test" {
+		t.Errorf("expected event to be %s. received %s", "Test", eventType)
+	}
+
+	venuePtr := int(le.Uint16(out[eventPtr+1152 : eventPtr+1154]))
+	if venue := ldText(venuePtr, 64); venue != "Red Bull Ring" {
+		t.Errorf("expected venue to be %s. received %s", "Red Bull Ring", venue)
+	}
+
+	type channel struct {
+		dataPtr, count    int
+		dtype, freq       uint16
+		name, short, unit string
+	}
+
+	channels := make(map[string]channel)
+	for ptr := int(le.Uint32(out[8:12])); ptr != 0; ptr = int(le.Uint32(out[ptr+4 : ptr+8])) {
+		c := channel{
+			dataPtr: int(le.Uint32(out[ptr+8 : ptr+12])),
+			count:   int(le.Uint32(out[ptr+12 : ptr+16])),
+			dtype:   le.Uint16(out[ptr+18 : ptr+20]),
+			freq:    le.Uint16(out[ptr+22 : ptr+24]),
+			name:    ldText(ptr+32, 32),
+			short:   ldText(ptr+64, 8),
+			unit:    ldText(ptr+72, 12),
+		}
+		channels[c.name] = c
+	}
+
+	speed, ok := channels["Speed"]
+	if !ok || speed.unit != "m/s" || speed.freq != 60 || speed.count != 390 || speed.dtype != ldFloatType {
+		t.Errorf("expected Speed to be a 60Hz float channel in m/s with 390 samples. received %+v", speed)
+	}
+
+	frame, _ := ibt.LoadChannels(stubs[0], "Speed")
+	speeds, _ := ibt.GetColumn[[]float32](frame, "Speed")
+	if value := math.Float32frombits(le.Uint32(out[speed.dataPtr+100*4:])); value != speeds[100] {
+		t.Errorf("expected Speed sample %d to be %f. received %f", 100, speeds[100], value)
+	}
+
+	lap := channels["Lap"]
+	if lap.dtype != ldIntType || int32(le.Uint32(out[lap.dataPtr:])) != 9 {
+		t.Errorf("expected Lap to be an integer channel starting at %d. received %+v", 9, lap)
+	}
+
+	torque := channels["SteeringWheelTorque_ST"]
+	if torque.freq != 360 || torque.count != 390*6 || torque.short != "Steering" {
+		t.Errorf("expected SteeringWheelTorque_ST to be a 360Hz channel with %d samples. received %+v", 390*6, torque)
+	}
+
+	if err := WriteLD(new(bytes.Buffer), stubs[0], "NotFound"); err == nil {
+		t.Error("expected WriteLD() to return an error for a missing variable")
+	}
+}
+
+func TestWriteLDX(t *testing.T) {
+	stubs, err := ibt.ParseStubs("../.testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to parse stubs for testing file - %v", err)
+		return
+	}
+	defer stubs.Close()
+
+	buf := new(bytes.Buffer)
+	if err := WriteLDX(buf, stubs[0]); err != nil {
+		t.Errorf("expected WriteLDX() to run without err. received error: %v", err)
+		return
+	}
+
+	var ldx ldxFile
+	if err := xml.Unmarshal(buf.Bytes(), &ldx); err != nil {
+		t.Errorf("expected output to be valid xml. received error: %v", err)
+		return
+	}
+
+	if markers := ldx.Layers.Layer.MarkerBlock.MarkerGroup.Markers; len(markers) != 0 {
+		t.Errorf("expected no lap beacons for a single lap. received %v", markers)
+	}
+
+	if len(ldx.Layers.Details) != 1 || ldx.Layers.Details[0].Value != "1" {
+		t.Errorf("expected Total Laps to be %d. received %v", 1, ldx.Layers.Details)
+	}
+}
@@ -0,0 +1,551 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/teamjorge/ibt"
+	"github.com/teamjorge/ibt/headers"
+)
+
+const (
+	// Default number of ticks stored in a single Parquet row group
+	defaultParquetRowGroupSize int = 36000
+
+	parquetMagic string = "PAR1"
+)
+
+// Parquet physical types
+const (
+	parquetBoolean int32 = 0
+	parquetInt32   int32 = 1
+	parquetFloat   int32 = 4
+	parquetDouble  int32 = 5
+)
+
+// Parquet converted types
+const (
+	parquetNoConvertedType int32 = -1
+	parquetList            int32 = 3
+	parquetUint8           int32 = 11
+	parquetUint32          int32 = 13
+)
+
+// Parquet repetition types
+const (
+	parquetRequired int32 = 0
+	parquetRepeated int32 = 2
+)
+
+// Parquet encodings
+const (
+	parquetPlain int32 = 0
+	parquetRLE   int32 = 3
+)
+
+// ParquetOptions configures a ParquetWriter.
+type ParquetOptions struct {
+	// RowGroupSize is the number of ticks stored in a single row group. Defaults to 36000 (10 minutes at 60Hz).
+	//
+	// Only a single row group is kept in memory, which allows entire files to be streamed.
+	RowGroupSize int
+	// Filter for the exported ticks
+	Filter
+}
+
+// ParquetWriter is a processor that writes telemetry ticks to an Apache Parquet file.
+//
+// Each variable is written as a column. Array variables are written as list columns. Session metadata, such as the
+// track, car, SubSessionID and driver, is written to the key/value metadata of the file.
+//
+// Close must be called once processing is completed to write the remaining ticks and the file footer.
+type ParquetWriter struct {
+	w         *countingWriter
+	columns   []*parquetColumn
+	whitelist []string
+	filter    *tickFilter
+	metadata  [][2]string
+
+	rowGroupSize int
+	rows         int
+	rowGroups    []parquetRowGroup
+	totalRows    int64
+}
+
+// NewParquetWriter creates a new ParquetWriter for the given stubs and variable whitelist.
+//
+// The variables of the first stub are used to determine the columns. If no variables or a single value
+// of "*" is received, all variables will be exported.
+func NewParquetWriter(w io.Writer, stubs ibt.StubGroup, whitelist []string, opts ParquetOptions) (*ParquetWriter, error) {
+	fields, whitelist, err := resolveFields(stubs, whitelist)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &ParquetWriter{
+		w:            &countingWriter{w: w},
+		whitelist:    whitelist,
+		filter:       newTickFilter(opts.Filter),
+		metadata:     parquetSessionMetadata(stubs[0].Headers()),
+		rowGroupSize: opts.RowGroupSize,
+	}
+
+	if p.rowGroupSize <= 0 {
+		p.rowGroupSize = defaultParquetRowGroupSize
+	}
+
+	for _, f := range fields {
+		// Array variables are written as a single list column
+		if f.index > 0 {
+			continue
+		}
+
+		p.columns = append(p.columns, newParquetColumn(f.header))
+	}
+
+	if _, err := p.w.Write([]byte(parquetMagic)); err != nil {
+		return nil, fmt.Errorf("failed to write parquet header: %v", err)
+	}
+
+	return p, nil
+}
+
+// Process adds the given tick to the current row group.
+//
+// The row group is written once it reaches the configured RowGroupSize.
+func (p *ParquetWriter) Process(input ibt.Tick, hasNext bool, session *headers.Session) error {
+	if !p.filter.keep(input) {
+		return nil
+	}
+
+	for _, column := range p.columns {
+		column.add(input[column.header.Name])
+	}
+	p.rows++
+
+	if p.rows >= p.rowGroupSize {
+		return p.flushRowGroup()
+	}
+
+	return nil
+}
+
+// Whitelist of the variables required for the export
+func (p *ParquetWriter) Whitelist() []string { return p.filter.whitelist(p.whitelist) }
+
+// Close writes any remaining ticks and the file footer.
+//
+// The underlying writer is not closed.
+func (p *ParquetWriter) Close() error {
+	if p.rows > 0 {
+		if err := p.flushRowGroup(); err != nil {
+			return err
+		}
+	}
+
+	footer := p.fileMetadata()
+
+	length := make([]byte, 4)
+	binary.LittleEndian.PutUint32(length, uint32(len(footer)))
+
+	for _, b := range [][]byte{footer, length, []byte(parquetMagic)} {
+		if _, err := p.w.Write(b); err != nil {
+			return fmt.Errorf("failed to write parquet footer: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// flushRowGroup writes a column chunk for every column and resets the columns for the next row group.
+func (p *ParquetWriter) flushRowGroup() error {
+	rowGroup := parquetRowGroup{numRows: int64(p.rows)}
+	start := p.w.n
+
+	for _, column := range p.columns {
+		offset := p.w.n
+
+		page := column.page()
+		if _, err := p.w.Write(page); err != nil {
+			return fmt.Errorf("failed to write parquet column %s: %v", column.header.Name, err)
+		}
+
+		rowGroup.chunks = append(rowGroup.chunks, parquetColumnChunk{
+			column:    column,
+			offset:    offset,
+			size:      int64(len(page)),
+			numValues: int64(column.numValues),
+		})
+
+		column.reset()
+	}
+
+	rowGroup.size = p.w.n - start
+
+	p.rowGroups = append(p.rowGroups, rowGroup)
+	p.totalRows += int64(p.rows)
+	p.rows = 0
+
+	return nil
+}
+
+// fileMetadata encodes the FileMetaData footer of the file.
+func (p *ParquetWriter) fileMetadata() []byte {
+	w := newCompactWriter()
+
+	w.structBegin()
+	w.i32Field(1, 1)
+
+	// Schema is flattened in depth-first order, starting with the root
+	numSchemaElements := 1
+	for _, column := range p.columns {
+		numSchemaElements += len(column.schema())
+	}
+
+	w.listField(2, thriftStruct, numSchemaElements)
+	writeSchemaElement(w, parquetSchemaElement{name: "schema", physical: -1, repetition: -1, numChildren: len(p.columns), converted: parquetNoConvertedType})
+	for _, column := range p.columns {
+		for _, element := range column.schema() {
+			writeSchemaElement(w, element)
+		}
+	}
+
+	w.i64Field(3, p.totalRows)
+
+	w.listField(4, thriftStruct, len(p.rowGroups))
+	for _, rowGroup := range p.rowGroups {
+		rowGroup.write(w)
+	}
+
+	w.listField(5, thriftStruct, len(p.metadata))
+	for _, kv := range p.metadata {
+		w.structBegin()
+		w.stringField(1, kv[0])
+		w.stringField(2, kv[1])
+		w.structEnd()
+	}
+
+	w.stringField(6, "github.com/teamjorge/ibt")
+	w.structEnd()
+
+	return w.Bytes()
+}
+
+// parquetSessionMetadata extracts the session information written to the key/value metadata of the file.
+func parquetSessionMetadata(header *headers.Header) [][2]string {
+	session := header.SessionInfo
+
+	metadata := [][2]string{
+		{"ibt.track", session.WeekendInfo.TrackName},
+		{"ibt.track_display_name", session.WeekendInfo.TrackDisplayName},
+		{"ibt.track_config_name", session.WeekendInfo.TrackConfigName},
+		{"ibt.session_id", strconv.Itoa(session.WeekendInfo.SessionID)},
+		{"ibt.sub_session_id", strconv.Itoa(session.WeekendInfo.SubSessionID)},
+		{"ibt.event_type", session.WeekendInfo.EventType},
+		{"ibt.tick_rate", strconv.Itoa(header.TelemetryHeader.TickRate)},
+	}
+
+	if header.DiskHeader != nil {
+		metadata = append(metadata, [2]string{"ibt.start_date", strconv.FormatInt(header.DiskHeader.StartDate, 10)})
+	}
+
+	if driver := session.GetDriver(); driver != nil {
+		metadata = append(metadata,
+			[2]string{"ibt.car", driver.CarPath},
+			[2]string{"ibt.car_screen_name", driver.CarScreenName},
+			[2]string{"ibt.driver", driver.UserName},
+			[2]string{"ibt.driver_user_id", strconv.Itoa(driver.UserID)},
+		)
+	}
+
+	return metadata
+}
+
+// parquetColumn buffers the encoded values of a single variable for the current row group.
+type parquetColumn struct {
+	header    headers.VarHeader
+	physical  int32
+	converted int32
+
+	values    bytes.Buffer
+	bools     []bool
+	numValues int
+}
+
+func newParquetColumn(vh headers.VarHeader) *parquetColumn {
+	c := &parquetColumn{header: vh, converted: parquetNoConvertedType}
+
+	switch vh.Rtype {
+	case 0:
+		c.physical, c.converted = parquetInt32, parquetUint8
+	case 1:
+		c.physical = parquetBoolean
+	case 2:
+		c.physical = parquetInt32
+	case 3:
+		c.physical, c.converted = parquetInt32, parquetUint32
+	case 4:
+		c.physical = parquetFloat
+	case 5:
+		c.physical = parquetDouble
+	}
+
+	return c
+}
+
+// isList determines if the column is written as a list of values
+func (c *parquetColumn) isList() bool { return c.header.Count > 1 }
+
+// add encodes the given tick value. Missing values are written as the zero value of the column.
+func (c *parquetColumn) add(value interface{}) {
+	if !c.isList() {
+		c.addItem(value)
+		return
+	}
+
+	rv := reflect.ValueOf(value)
+	for i := 0; i < c.header.Count; i++ {
+		if rv.Kind() == reflect.Slice && i < rv.Len() {
+			c.addItem(rv.Index(i).Interface())
+		} else {
+			c.addItem(nil)
+		}
+	}
+}
+
+func (c *parquetColumn) addItem(value interface{}) {
+	c.numValues++
+
+	switch c.physical {
+	case parquetBoolean:
+		v, _ := value.(bool)
+		c.bools = append(c.bools, v)
+	case parquetInt32:
+		c.values.Write(binary.LittleEndian.AppendUint32(nil, toUint32(value)))
+	case parquetFloat:
+		v, _ := value.(float32)
+		c.values.Write(binary.LittleEndian.AppendUint32(nil, math.Float32bits(v)))
+	case parquetDouble:
+		v, _ := value.(float64)
+		c.values.Write(binary.LittleEndian.AppendUint64(nil, math.Float64bits(v)))
+	}
+}
+
+// page encodes the buffered values as a single PLAIN encoded data page, including the page header.
+func (c *parquetColumn) page() []byte {
+	data := new(bytes.Buffer)
+
+	if c.isList() {
+		rows := c.numValues / c.header.Count
+
+		// Repetition levels: 0 for the first item of every row and 1 for the remaining items
+		runs := make([][2]int, 0, rows*2)
+		for i := 0; i < rows; i++ {
+			runs = append(runs, [2]int{1, 0}, [2]int{c.header.Count - 1, 1})
+		}
+		writeLevels(data, runs)
+
+		// Definition levels: every item is defined
+		writeLevels(data, [][2]int{{c.numValues, 1}})
+	}
+
+	if c.physical == parquetBoolean {
+		packed := make([]byte, (len(c.bools)+7)/8)
+		for i, v := range c.bools {
+			if v {
+				packed[i/8] |= 1 << (i % 8)
+			}
+		}
+		data.Write(packed)
+	} else {
+		data.Write(c.values.Bytes())
+	}
+
+	w := newCompactWriter()
+	w.structBegin()
+	w.i32Field(1, 0)
+	w.i32Field(2, int32(data.Len()))
+	w.i32Field(3, int32(data.Len()))
+	w.structField(5)
+	w.i32Field(1, int32(c.numValues))
+	w.i32Field(2, parquetPlain)
+	w.i32Field(3, parquetRLE)
+	w.i32Field(4, parquetRLE)
+	w.structEnd()
+	w.structEnd()
+
+	return append(w.Bytes(), data.Bytes()...)
+}
+
+func (c *parquetColumn) reset() {
+	c.values.Reset()
+	c.bools = c.bools[:0]
+	c.numValues = 0
+}
+
+// schema elements of the column. List columns use the standard three-level list representation.
+func (c *parquetColumn) schema() []parquetSchemaElement {
+	leaf := parquetSchemaElement{name: c.header.Name, physical: c.physical, repetition: parquetRequired, converted: c.converted, numChildren: -1}
+
+	if !c.isList() {
+		return []parquetSchemaElement{leaf}
+	}
+
+	leaf.name = "element"
+
+	return []parquetSchemaElement{
+		{name: c.header.Name, physical: -1, repetition: parquetRequired, converted: parquetList, numChildren: 1},
+		{name: "list", physical: -1, repetition: parquetRepeated, converted: parquetNoConvertedType, numChildren: 1},
+		leaf,
+	}
+}
+
+// path of the leaf column in the schema
+func (c *parquetColumn) path() []string {
+	if !c.isList() {
+		return []string{c.header.Name}
+	}
+
+	return []string{c.header.Name, "list", "element"}
+}
+
+// writeLevels encodes repetition or definition levels with the RLE/bit-packing hybrid encoding and a bit width of 1.
+//
+// Each run consists of the number of repeated values and the value itself.
+func writeLevels(buf *bytes.Buffer, runs [][2]int) {
+	encoded := make([]byte, 0)
+	for _, run := range runs {
+		if run[0] == 0 {
+			continue
+		}
+
+		encoded = binary.AppendUvarint(encoded, uint64(run[0])<<1)
+		encoded = append(encoded, byte(run[1]))
+	}
+
+	buf.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(encoded))))
+	buf.Write(encoded)
+}
+
+// toUint32 converts integer and bitfield values to uint32.
+//
+// Bitfields represented as hexadecimal strings (0x...) are also supported.
+func toUint32(value interface{}) uint32 {
+	switch v := value.(type) {
+	case nil:
+		return 0
+	case string:
+		parsed, _ := strconv.ParseUint(strings.TrimPrefix(v, "0x"), 16, 32)
+		return uint32(parsed)
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return uint32(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return uint32(rv.Uint())
+	}
+
+	return 0
+}
+
+type parquetSchemaElement struct {
+	name        string
+	physical    int32
+	repetition  int32
+	converted   int32
+	numChildren int
+}
+
+func writeSchemaElement(w *compactWriter, e parquetSchemaElement) {
+	w.structBegin()
+	if e.physical >= 0 {
+		w.i32Field(1, e.physical)
+	}
+	if e.repetition >= 0 {
+		w.i32Field(3, e.repetition)
+	}
+	w.stringField(4, e.name)
+	if e.numChildren >= 0 {
+		w.i32Field(5, int32(e.numChildren))
+	}
+	if e.converted >= 0 {
+		w.i32Field(6, e.converted)
+	}
+	w.structEnd()
+}
+
+type parquetRowGroup struct {
+	chunks  []parquetColumnChunk
+	size    int64
+	numRows int64
+}
+
+func (rg parquetRowGroup) write(w *compactWriter) {
+	w.structBegin()
+
+	w.listField(1, thriftStruct, len(rg.chunks))
+	for _, chunk := range rg.chunks {
+		chunk.write(w)
+	}
+
+	w.i64Field(2, rg.size)
+	w.i64Field(3, rg.numRows)
+	w.structEnd()
+}
+
+type parquetColumnChunk struct {
+	column    *parquetColumn
+	offset    int64
+	size      int64
+	numValues int64
+}
+
+func (cc parquetColumnChunk) write(w *compactWriter) {
+	w.structBegin()
+	w.i64Field(2, cc.offset)
+
+	w.structField(3)
+	w.i32Field(1, cc.column.physical)
+
+	encodings := []int32{parquetPlain, parquetRLE}
+	w.listField(2, thriftI32, len(encodings))
+	for _, encoding := range encodings {
+		w.zigzag(int64(encoding))
+	}
+
+	path := cc.column.path()
+	w.listField(3, thriftBinary, len(path))
+	for _, p := range path {
+		w.str(p)
+	}
+
+	w.i32Field(4, 0)
+	w.i64Field(5, cc.numValues)
+	w.i64Field(6, cc.size)
+	w.i64Field(7, cc.size)
+	w.i64Field(9, cc.offset)
+	w.structEnd()
+
+	w.structEnd()
+}
+
+// countingWriter keeps track of the number of bytes written, which is required for the file offsets.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
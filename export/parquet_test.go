@@ -0,0 +1,96 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"testing"
+
+	"github.com/teamjorge/ibt"
+)
+
+func TestParquetWriter(t *testing.T) {
+	stubs, err := ibt.ParseStubs("../.testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to parse stubs for testing file - %v", err)
+		return
+	}
+	defer stubs.Close()
+
+	t.Run("test ParquetWriter file layout", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+
+		w, err := NewParquetWriter(buf, stubs, []string{"Speed", "Lap", "OnPitRoad", "SessionTime", "SessionFlags", "SteeringWheelTorque_ST"}, ParquetOptions{RowGroupSize: 100})
+		if err != nil {
+			t.Errorf("expected NewParquetWriter() to run without err. received error: %v", err)
+			return
+		}
+
+		if err := ibt.Process(context.Background(), stubs, w); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+		}
+
+		if err := w.Close(); err != nil {
+			t.Errorf("expected Close() to run without err. received error: %v", err)
+		}
+
+		out := buf.Bytes()
+		if string(out[:4]) != parquetMagic || string(out[len(out)-4:]) != parquetMagic {
+			t.Errorf("expected file to start and end with %s", parquetMagic)
+		}
+
+		footerLength := int(binary.LittleEndian.Uint32(out[len(out)-8 : len(out)-4]))
+		footer := out[len(out)-8-footerLength : len(out)-8]
+
+		for _, expected := range []string{"SteeringWheelTorque_ST", "element", "ibt.track", "spielberg gp", "ibt.driver_user_id", "450313"} {
+			if !bytes.Contains(footer, []byte(expected)) {
+				t.Errorf("expected footer to contain %s", expected)
+			}
+		}
+
+		if len(w.rowGroups) != 4 || w.totalRows != 390 {
+			t.Errorf("expected %d rows in %d row groups. received %d rows in %d row groups", 390, 4, w.totalRows, len(w.rowGroups))
+		}
+
+		if w.rowGroups[0].chunks[5].numValues != 600 {
+			t.Errorf("expected list column to have %d values in the first row group. received %d", 600, w.rowGroups[0].chunks[5].numValues)
+		}
+	})
+
+	t.Run("test NewParquetWriter invalid variables", func(t *testing.T) {
+		if _, err := NewParquetWriter(new(bytes.Buffer), stubs, []string{"NotFound"}, ParquetOptions{}); err == nil {
+			t.Error("expected NewParquetWriter() to return an error for a missing variable")
+		}
+	})
+}
+
+func TestParquetEncoding(t *testing.T) {
+	t.Run("test writeLevels", func(t *testing.T) {
+		buf := new(bytes.Buffer)
+		writeLevels(buf, [][2]int{{1, 0}, {0, 1}, {5, 1}})
+
+		expected := []byte{0x4, 0x0, 0x0, 0x0, 0x2, 0x0, 0xa, 0x1}
+		if !bytes.Equal(buf.Bytes(), expected) {
+			t.Errorf("expected encoded levels to be %v. received %v", expected, buf.Bytes())
+		}
+	})
+
+	t.Run("test toUint32", func(t *testing.T) {
+		if toUint32("0x10000") != 65536 || toUint32(-1) != 4294967295 || toUint32(uint8(3)) != 3 || toUint32(nil) != 0 {
+			t.Error("expected values to be converted to uint32")
+		}
+	})
+
+	t.Run("test compactWriter field deltas", func(t *testing.T) {
+		w := newCompactWriter()
+		w.structBegin()
+		w.i32Field(1, 1)
+		w.i64Field(20, -1)
+		w.structEnd()
+
+		expected := []byte{0x15, 0x2, 0x6, 0x28, 0x1, 0x0}
+		if !bytes.Equal(w.Bytes(), expected) {
+			t.Errorf("expected encoded struct to be %v. received %v", expected, w.Bytes())
+		}
+	})
+}
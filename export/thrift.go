@@ -0,0 +1,104 @@
+package export
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// Thrift compact protocol types used by the Parquet file metadata
+const (
+	thriftBinary byte = 8
+	thriftI32    byte = 5
+	thriftI64    byte = 6
+	thriftList   byte = 9
+	thriftStruct byte = 12
+)
+
+// compactWriter is a minimal write-only implementation of the Thrift compact protocol.
+//
+// Only the types required to write Parquet metadata are supported.
+type compactWriter struct {
+	buf bytes.Buffer
+
+	// Last written field id for each nested struct
+	lastField []int16
+}
+
+func newCompactWriter() *compactWriter {
+	return &compactWriter{lastField: []int16{0}}
+}
+
+// Bytes of the encoded output
+func (w *compactWriter) Bytes() []byte { return w.buf.Bytes() }
+
+func (w *compactWriter) varint(v uint64) {
+	w.buf.Write(binary.AppendUvarint(nil, v))
+}
+
+func (w *compactWriter) zigzag(v int64) {
+	w.varint(uint64((v << 1) ^ (v >> 63)))
+}
+
+func (w *compactWriter) fieldHeader(id int16, typ byte) {
+	last := w.lastField[len(w.lastField)-1]
+
+	if delta := id - last; delta > 0 && delta <= 15 {
+		w.buf.WriteByte(byte(delta<<4) | typ)
+	} else {
+		w.buf.WriteByte(typ)
+		w.zigzag(int64(id))
+	}
+
+	w.lastField[len(w.lastField)-1] = id
+}
+
+func (w *compactWriter) i32Field(id int16, v int32) {
+	w.fieldHeader(id, thriftI32)
+	w.zigzag(int64(v))
+}
+
+func (w *compactWriter) i64Field(id int16, v int64) {
+	w.fieldHeader(id, thriftI64)
+	w.zigzag(v)
+}
+
+func (w *compactWriter) stringField(id int16, v string) {
+	w.fieldHeader(id, thriftBinary)
+	w.str(v)
+}
+
+func (w *compactWriter) str(v string) {
+	w.varint(uint64(len(v)))
+	w.buf.WriteString(v)
+}
+
+// listField writes the header of a list field. The elements should be written directly after.
+func (w *compactWriter) listField(id int16, elemType byte, size int) {
+	w.fieldHeader(id, thriftList)
+	w.listHeader(elemType, size)
+}
+
+func (w *compactWriter) listHeader(elemType byte, size int) {
+	if size < 15 {
+		w.buf.WriteByte(byte(size<<4) | elemType)
+		return
+	}
+
+	w.buf.WriteByte(0xf0 | elemType)
+	w.varint(uint64(size))
+}
+
+// structField writes the header of a struct field and begins the struct.
+func (w *compactWriter) structField(id int16) {
+	w.fieldHeader(id, thriftStruct)
+	w.structBegin()
+}
+
+// structBegin starts a new struct. This is used directly for structs in lists.
+func (w *compactWriter) structBegin() { w.lastField = append(w.lastField, 0) }
+
+// structEnd writes the stop field of the current struct
+func (w *compactWriter) structEnd() {
+	w.buf.WriteByte(0)
+	w.lastField = w.lastField[:len(w.lastField)-1]
+}
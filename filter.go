@@ -0,0 +1,239 @@
+package ibt
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+// StubFilter is a predicate that determines whether a stub should be kept by StubGroup.Filter.
+type StubFilter func(stub Stub) bool
+
+// Filter returns the stubs that match all of the given filters.
+//
+// For example, the race sessions of a car at a track since a given time:
+//
+//	stubs.Filter(ByTrack("spa"), ByCar("mx5"), Since(t), BySessionType("Race"))
+func (stubs StubGroup) Filter(filters ...StubFilter) StubGroup {
+	filtered := make(StubGroup, 0)
+
+	for _, stub := range stubs {
+		if All(filters...)(stub) {
+			filtered = append(filtered, stub)
+		}
+	}
+
+	return filtered
+}
+
+// All matches stubs that match all of the given filters.
+func All(filters ...StubFilter) StubFilter {
+	return func(stub Stub) bool {
+		for _, filter := range filters {
+			if !filter(stub) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// Any matches stubs that match at least one of the given filters.
+func Any(filters ...StubFilter) StubFilter {
+	return func(stub Stub) bool {
+		for _, filter := range filters {
+			if filter(stub) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// Not matches stubs that do not match the given filter.
+func Not(filter StubFilter) StubFilter {
+	return func(stub Stub) bool { return !filter(stub) }
+}
+
+// ByTrack matches stubs of which the track contains the given name.
+//
+// The name is matched case-insensitively against the internal and display names of the track,
+// for example "spa" will match "spa 2024 up" and "Circuit de Spa-Francorchamps".
+func ByTrack(name string) StubFilter {
+	return func(stub Stub) bool {
+		session := stubSession(stub)
+		if session == nil {
+			return false
+		}
+
+		weekend := session.WeekendInfo
+
+		return containsFold(name, weekend.TrackName, weekend.TrackDisplayName, weekend.TrackDisplayShortName)
+	}
+}
+
+// ByCar matches stubs of which the car of the driver contains the given name.
+//
+// The name is matched case-insensitively against the path and screen names of the car, for example
+// "mx5" will match "mx5 mx52016" and "Mercedes" will match "Mercedes-AMG W13 E Performance".
+func ByCar(name string) StubFilter {
+	return func(stub Stub) bool {
+		driver := stubDriver(stub)
+		if driver == nil {
+			return false
+		}
+
+		return containsFold(name, driver.CarPath, driver.CarScreenName, driver.CarScreenNameShort)
+	}
+}
+
+// BySessionType matches stubs that recorded a session of the given type, for example "Race", "Practice",
+// or "Offline Testing". The type is matched case-insensitively against the type and name of each session.
+//
+// Only the header of the stub is used, which means every session of the event is matched, including
+// sessions that the stub might not have recorded.
+func BySessionType(sessionType string) StubFilter {
+	return func(stub Stub) bool {
+		session := stubSession(stub)
+		if session == nil {
+			return false
+		}
+
+		for _, s := range session.SessionInfo.Sessions {
+			if strings.EqualFold(s.SessionType, sessionType) || strings.EqualFold(s.SessionName, sessionType) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// ByDriver matches stubs of which the driver's name contains the given name, ignoring case.
+func ByDriver(name string) StubFilter {
+	return func(stub Stub) bool {
+		driver := stubDriver(stub)
+		return driver != nil && containsFold(name, driver.UserName)
+	}
+}
+
+// ByUserID matches stubs of the driver with the given iRacing user ID.
+func ByUserID(userID int) StubFilter {
+	return func(stub Stub) bool {
+		driver := stubDriver(stub)
+		return driver != nil && driver.UserID == userID
+	}
+}
+
+// Official matches stubs of official iRacing sessions.
+func Official() StubFilter {
+	return func(stub Stub) bool {
+		session := stubSession(stub)
+		return session != nil && session.WeekendInfo.Official == 1
+	}
+}
+
+// TestSession matches stubs of test sessions, which do not have a SubSessionID.
+func TestSession() StubFilter {
+	return func(stub Stub) bool {
+		session := stubSession(stub)
+		return session != nil && session.WeekendInfo.SubSessionID == 0
+	}
+}
+
+// Since matches stubs created at or after the given time.
+func Since(t time.Time) StubFilter {
+	return func(stub Stub) bool { return hasStartDate(stub) && !stub.Time().Before(t) }
+}
+
+// Until matches stubs created before the given time.
+func Until(t time.Time) StubFilter {
+	return func(stub Stub) bool { return hasStartDate(stub) && stub.Time().Before(t) }
+}
+
+// GroupBy groups the stubs by the key returned for each stub.
+//
+// The stubs of each group are sorted by time. Use GroupBy for grouping by something other than the
+// iRacing session, for which Group should be used instead.
+func (stubs StubGroup) GroupBy(key func(stub Stub) string) map[string]StubGroup {
+	groups := make(map[string]StubGroup)
+
+	for _, stub := range stubs {
+		k := key(stub)
+		groups[k] = append(groups[k], stub)
+	}
+
+	for _, group := range groups {
+		sort.Sort(group)
+	}
+
+	return groups
+}
+
+// CarKey is a GroupBy key of the path of the driver's car, for example "mercedesw13".
+func CarKey(stub Stub) string {
+	if driver := stubDriver(stub); driver != nil {
+		return driver.CarPath
+	}
+
+	return ""
+}
+
+// TrackKey is a GroupBy key of the internal name of the track, which includes it's configuration, for example "spielberg gp".
+func TrackKey(stub Stub) string {
+	if session := stubSession(stub); session != nil {
+		return session.WeekendInfo.TrackName
+	}
+
+	return ""
+}
+
+// WeekKey is a GroupBy key of the ISO year and week (in UTC) when the stub was created, for example "2024-W26".
+func WeekKey(stub Stub) string {
+	if !hasStartDate(stub) {
+		return ""
+	}
+
+	year, week := stub.Time().UTC().ISOWeek()
+
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// stubSession returns the session info of the stub, or nil if it has not been parsed.
+func stubSession(stub Stub) *headers.Session {
+	if stub.header == nil {
+		return nil
+	}
+
+	return stub.header.SessionInfo
+}
+
+// stubDriver returns the driver of the stub, or nil if it is not available.
+func stubDriver(stub Stub) *headers.Drivers {
+	session := stubSession(stub)
+	if session == nil {
+		return nil
+	}
+
+	return session.GetDriver()
+}
+
+func hasStartDate(stub Stub) bool { return stub.header != nil && stub.header.DiskHeader != nil }
+
+// containsFold returns true if any of the values contain the substring, ignoring case.
+func containsFold(substr string, values ...string) bool {
+	substr = strings.ToLower(substr)
+
+	for _, value := range values {
+		if value != "" && strings.Contains(strings.ToLower(value), substr) {
+			return true
+		}
+	}
+
+	return false
+}
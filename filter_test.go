@@ -0,0 +1,120 @@
+package ibt
+
+import (
+	"testing"
+	"time"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+func TestStubFilters(t *testing.T) {
+	stubs, err := ParseStubs(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to parse stubs for testing file - %v", err)
+	}
+	defer stubs.Close()
+
+	stub := stubs[0]
+	created := stub.Time()
+
+	// A stub without any headers should never match and never panic
+	empty := Stub{filepath: "empty.ibt", header: &headers.Header{}}
+
+	testCases := []struct {
+		name     string
+		filter   StubFilter
+		expected bool
+	}{
+		{"ByTrack internal name", ByTrack("spiel"), true},
+		{"ByTrack display name", ByTrack("red bull"), true},
+		{"ByTrack other", ByTrack("spa"), false},
+		{"ByCar path", ByCar("mercedesw13"), true},
+		{"ByCar screen name", ByCar("AMG W13"), true},
+		{"ByCar other", ByCar("mx5"), false},
+		{"BySessionType session name", BySessionType("testing"), true},
+		{"BySessionType session type", BySessionType("Offline Testing"), true},
+		{"BySessionType other", BySessionType("Race"), false},
+		{"ByDriver", ByDriver("george"), true},
+		{"ByUserID", ByUserID(450313), true},
+		{"ByUserID other", ByUserID(1), false},
+		{"Official", Official(), false},
+		{"TestSession", TestSession(), true},
+		{"Since before", Since(created.Add(-time.Hour)), true},
+		{"Since equal", Since(created), true},
+		{"Since after", Since(created.Add(time.Hour)), false},
+		{"Until after", Until(created.Add(time.Hour)), true},
+		{"Until equal", Until(created), false},
+		{"Any", Any(ByCar("mx5"), ByTrack("red bull")), true},
+		{"All", All(ByCar("mx5"), ByTrack("red bull")), false},
+		{"Not", Not(Official()), true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if received := tc.filter(stub); received != tc.expected {
+				t.Errorf("expected filter to return %v. received %v", tc.expected, received)
+			}
+
+			if tc.filter(empty) && tc.name != "Not" {
+				t.Error("expected filter to not match a stub without headers")
+			}
+		})
+	}
+}
+
+func TestStubGroupFilter(t *testing.T) {
+	stubs, err := ParseStubs(".testing/valid_test_file.ibt", ".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to parse stubs for testing file - %v", err)
+	}
+	defer stubs.Close()
+
+	t.Run("test Filter match", func(t *testing.T) {
+		filtered := stubs.Filter(ByTrack("spielberg"), ByCar("mercedes"), BySessionType("Offline Testing"))
+		if len(filtered) != 2 {
+			t.Errorf("expected %d stubs to match. received %d", 2, len(filtered))
+		}
+	})
+
+	t.Run("test Filter no match", func(t *testing.T) {
+		filtered := stubs.Filter(ByTrack("spielberg"), ByCar("mx5"))
+		if len(filtered) != 0 {
+			t.Errorf("expected %d stubs to match. received %d", 0, len(filtered))
+		}
+	})
+
+	t.Run("test Filter no filters", func(t *testing.T) {
+		if filtered := stubs.Filter(); len(filtered) != 2 {
+			t.Errorf("expected all stubs to be returned without filters. received %d", len(filtered))
+		}
+	})
+}
+
+func TestStubGroupGroupBy(t *testing.T) {
+	stubs, err := ParseStubs(".testing/valid_test_file.ibt", ".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to parse stubs for testing file - %v", err)
+	}
+	defer stubs.Close()
+
+	t.Run("test GroupBy CarKey", func(t *testing.T) {
+		groups := stubs.GroupBy(CarKey)
+		if len(groups) != 1 || len(groups["mercedesw13"]) != 2 {
+			t.Errorf("expected a single group for mercedesw13. received %v", groups)
+		}
+	})
+
+	t.Run("test GroupBy TrackKey", func(t *testing.T) {
+		groups := stubs.GroupBy(TrackKey)
+		if len(groups["spielberg gp"]) != 2 {
+			t.Errorf("expected a single group for spielberg gp. received %v", groups)
+		}
+	})
+
+	t.Run("test GroupBy WeekKey", func(t *testing.T) {
+		groups := stubs.GroupBy(WeekKey)
+		if len(groups["2024-W26"]) != 2 {
+			t.Errorf("expected a single group for 2024-W26. received %v", groups)
+		}
+	})
+}
@@ -0,0 +1,197 @@
+package ibt
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+// Frame is a columnar representation of telemetry variables for an entire ibt file.
+type Frame struct {
+	// Number of ticks loaded into each column
+	Len int
+	// Loaded columns by variable name
+	Columns map[string]*Column
+}
+
+// Column is the loaded values of a single telemetry variable and it's metadata.
+type Column struct {
+	// Header of the variable, containing the unit and description
+	Header headers.VarHeader
+	// Values of the column.
+	//
+	// This is a typed slice based on the Rtype and Count of the variable. For example, a float variable
+	// will be a []float32 and a float array variable will be a [][]float32. Bitfields are loaded as uint32.
+	Values interface{}
+}
+
+// ColumnValueType is an interface containing all possible types for the values of a Column
+type ColumnValueType interface {
+	[]uint8 | [][]uint8 | []bool | [][]bool | []int | [][]int | []uint32 | [][]uint32 | []float32 | [][]float32 | []float64 | [][]float64
+}
+
+// LoadChannels reads the given variables for every tick of the stub into a columnar Frame.
+//
+// Each record of the stub is read exactly once and decoded directly from the raw buffer. Columns are sized
+// according to the RecordCount of the stub. If no variables or a single value of "*" is received, all variables will be loaded.
+func LoadChannels(stub Stub, vars ...string) (*Frame, error) {
+	return LoadChannelsRange(stub, 0, 0, vars...)
+}
+
+// LoadChannelsRange reads the given variables for the ticks from start up to (but excluding) end into a columnar Frame.
+//
+// An end of 0 will load until the end of the stub. This can be used with the Start and End of a Lap to load a single lap.
+func LoadChannelsRange(stub Stub, start, end int, vars ...string) (*Frame, error) {
+	header := stub.header
+	if header == nil || header.DiskHeader == nil {
+		return nil, fmt.Errorf("stub %s does not have the headers of an ibt file", stub.Filename())
+	}
+
+	if len(vars) == 0 || (len(vars) == 1 && vars[0] == "*") {
+		vars = headers.AvailableVars(header.VarHeader)
+	}
+
+	reader, closeReader, err := stub.reader()
+	if err != nil {
+		return nil, err
+	}
+	defer closeReader()
+
+	parser := NewParser(reader, header)
+	parser.SeekRange(start, end)
+
+	capacity := header.DiskHeader.RecordCount - start
+	if end > 0 {
+		capacity = end - start
+	}
+	if capacity < 0 {
+		capacity = 0
+	}
+
+	loaders := make([]columnLoader, 0, len(vars))
+	for _, name := range vars {
+		loader, err := newColumnLoader(parser, name, capacity)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load channel for stub %s: %v", stub.Filename(), err)
+		}
+
+		loaders = append(loaders, loader)
+	}
+
+	frame := &Frame{Columns: make(map[string]*Column, len(loaders))}
+
+	for {
+		raw, hasNext := parser.NextRaw()
+		if raw.buf == nil {
+			break
+		}
+
+		for _, loader := range loaders {
+			loader.load()
+		}
+		frame.Len++
+
+		if !hasNext {
+			break
+		}
+	}
+
+	for _, loader := range loaders {
+		column := loader.column()
+		frame.Columns[column.Header.Name] = column
+	}
+
+	return frame, nil
+}
+
+// GetColumn will retrieve and type assert the values of the given column.
+func GetColumn[T ColumnValueType](frame *Frame, key string) (T, error) {
+	var def T
+
+	column, ok := frame.Columns[key]
+	if !ok {
+		return def, fmt.Errorf("column %s not found in frame", key)
+	}
+
+	values, ok := column.Values.(T)
+	if !ok {
+		return def, fmt.Errorf("values of %s were %s not %s", key, reflect.TypeOf(column.Values).String(), reflect.TypeOf(def).String())
+	}
+
+	return values, nil
+}
+
+// columnLoader appends the value of a variable in the current raw tick of a Parser to a column.
+type columnLoader interface {
+	load()
+	column() *Column
+}
+
+// newColumnLoader creates the typed loader for the given variable.
+func newColumnLoader(p *Parser, name string, capacity int) (columnLoader, error) {
+	vh, ok := p.header.VarHeader[name]
+	if !ok {
+		return nil, fmt.Errorf("variable %s not found in var headers", name)
+	}
+
+	switch vh.Rtype {
+	case 0:
+		accessor, err := p.Uint8(name)
+		return newTypedColumnLoader(accessor, err, capacity)
+	case 1:
+		accessor, err := p.Bool(name)
+		return newTypedColumnLoader(accessor, err, capacity)
+	case 2:
+		accessor, err := p.Int(name)
+		return newTypedColumnLoader(accessor, err, capacity)
+	case 3:
+		accessor, err := p.BitField(name)
+		return newTypedColumnLoader(accessor, err, capacity)
+	case 4:
+		accessor, err := p.Float32(name)
+		return newTypedColumnLoader(accessor, err, capacity)
+	case 5:
+		accessor, err := p.Float64(name)
+		return newTypedColumnLoader(accessor, err, capacity)
+	}
+
+	return nil, fmt.Errorf("variable %s has an unknown rtype %d", name, vh.Rtype)
+}
+
+// newTypedColumnLoader creates a scalar or array loader depending on the count of the accessor's variable.
+func newTypedColumnLoader[T AccessorValueType](accessor Accessor[T], err error, capacity int) (columnLoader, error) {
+	if err != nil {
+		return nil, err
+	}
+
+	if accessor.Len() > 1 {
+		return &arrayColumnLoader[T]{accessor, make([][]T, 0, capacity)}, nil
+	}
+
+	return &scalarColumnLoader[T]{accessor, make([]T, 0, capacity)}, nil
+}
+
+type scalarColumnLoader[T AccessorValueType] struct {
+	accessor Accessor[T]
+	values   []T
+}
+
+func (l *scalarColumnLoader[T]) load() { l.values = append(l.values, l.accessor.Value()) }
+
+func (l *scalarColumnLoader[T]) column() *Column {
+	return &Column{Header: l.accessor.Header(), Values: l.values}
+}
+
+type arrayColumnLoader[T AccessorValueType] struct {
+	accessor Accessor[T]
+	values   [][]T
+}
+
+func (l *arrayColumnLoader[T]) load() {
+	l.values = append(l.values, l.accessor.Values(make([]T, 0, l.accessor.Len())))
+}
+
+func (l *arrayColumnLoader[T]) column() *Column {
+	return &Column{Header: l.accessor.Header(), Values: l.values}
+}
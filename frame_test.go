@@ -0,0 +1,118 @@
+package ibt
+
+import (
+	"os"
+	"testing"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+func TestLoadChannels(t *testing.T) {
+	f, err := os.Open(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to open testing file - %v", err)
+		return
+	}
+	defer f.Close()
+
+	testHeaders, err := headers.ParseHeaders(f)
+	if err != nil {
+		t.Errorf("failed to parse header for testing file - %v", err)
+		return
+	}
+
+	stub := Stub{filepath: ".testing/valid_test_file.ibt", header: testHeaders, r: f}
+
+	t.Run("test LoadChannels without disk header", func(t *testing.T) {
+		header := *testHeaders
+		header.DiskHeader = nil
+
+		if _, err := LoadChannels(Stub{filepath: stub.filepath, header: &header, r: f}, "Speed"); err == nil {
+			t.Error("expected LoadChannels() to return an error for a stub without a disk header")
+		}
+	})
+
+	t.Run("test LoadChannels() typed columns", func(t *testing.T) {
+		frame, err := LoadChannels(stub, "LapCurrentLapTime", "Lap", "OnPitRoad", "SessionTime", "SessionFlags", "SteeringWheelTorque_ST")
+		if err != nil {
+			t.Errorf("expected LoadChannels() to run without err. received error: %v", err)
+			return
+		}
+
+		if frame.Len != testHeaders.DiskHeader.RecordCount {
+			t.Errorf("expected frame to have %d rows. received %d", testHeaders.DiskHeader.RecordCount, frame.Len)
+		}
+
+		lapTimes, err := GetColumn[[]float32](frame, "LapCurrentLapTime")
+		if err != nil {
+			t.Errorf("expected GetColumn() to run without err. received error: %v", err)
+		}
+
+		if len(lapTimes) != frame.Len {
+			t.Errorf("expected column to have %d values. received %d", frame.Len, len(lapTimes))
+		}
+
+		if lapTimes[0] != 37.6619 || lapTimes[1] != 37.678566 || lapTimes[389] != 44.145233 {
+			t.Errorf("expected lap times to be %f, %f and %f. received %f, %f and %f",
+				37.6619, 37.678566, 44.145233, lapTimes[0], lapTimes[1], lapTimes[389])
+		}
+
+		if frame.Columns["LapCurrentLapTime"].Header.Unit != "s" {
+			t.Errorf("expected column unit to be %s. received %s", "s", frame.Columns["LapCurrentLapTime"].Header.Unit)
+		}
+
+		if _, err := GetColumn[[]int](frame, "Lap"); err != nil {
+			t.Errorf("expected Lap to be an int column. received error: %v", err)
+		}
+
+		if _, err := GetColumn[[]bool](frame, "OnPitRoad"); err != nil {
+			t.Errorf("expected OnPitRoad to be a bool column. received error: %v", err)
+		}
+
+		if _, err := GetColumn[[]float64](frame, "SessionTime"); err != nil {
+			t.Errorf("expected SessionTime to be a float64 column. received error: %v", err)
+		}
+
+		if _, err := GetColumn[[]uint32](frame, "SessionFlags"); err != nil {
+			t.Errorf("expected SessionFlags to be an uint32 column. received error: %v", err)
+		}
+
+		torque, err := GetColumn[[][]float32](frame, "SteeringWheelTorque_ST")
+		if err != nil {
+			t.Errorf("expected SteeringWheelTorque_ST to be a float array column. received error: %v", err)
+		}
+
+		if len(torque) != frame.Len || len(torque[0]) != 6 {
+			t.Errorf("expected torque column to have %d rows of %d values", frame.Len, 6)
+		}
+	})
+
+	t.Run("test LoadChannels() all variables", func(t *testing.T) {
+		frame, err := LoadChannels(stub)
+		if err != nil {
+			t.Errorf("expected LoadChannels() to run without err. received error: %v", err)
+		}
+
+		if len(frame.Columns) != 276 {
+			t.Errorf("expected frame to have %d columns. received %d", 276, len(frame.Columns))
+		}
+	})
+
+	t.Run("test LoadChannels() missing variable", func(t *testing.T) {
+		if _, err := LoadChannels(stub, "Speed", "NotFound"); err == nil {
+			t.Error("expected LoadChannels() to return an error for a missing variable")
+		}
+	})
+
+	t.Run("test GetColumn() invalid column", func(t *testing.T) {
+		frame, _ := LoadChannels(stub, "Speed")
+
+		if _, err := GetColumn[[]float32](frame, "NotFound"); err == nil {
+			t.Error("expected GetColumn() to return an error for a missing column")
+		}
+
+		if _, err := GetColumn[[]int](frame, "Speed"); err == nil {
+			t.Error("expected GetColumn() to return an error for an incorrect type")
+		}
+	})
+}
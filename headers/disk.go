@@ -15,15 +15,15 @@ const (
 // DiskHeader is the ibt file header indicating start, end, and amount of records
 type DiskHeader struct {
 	// Unix timestamp indicating the start date and time of the file
-	StartDate int64
+	StartDate int64 `json:"start_date"`
 	// Start time of file relative to the seconds since start of the session
-	StartTime float64
+	StartTime float64 `json:"start_time"`
 	// End time of file relative to the seconds since start of the session
-	EndTime float64
+	EndTime float64 `json:"end_time"`
 	// Number of laps telemetry exists for
-	LapCount int
+	LapCount int `json:"lap_count"`
 	// Number of telemetry variable records
-	RecordCount int
+	RecordCount int `json:"record_count"`
 }
 
 // ReadDiskHeader attempts to parse the Disk SubHeader from the given Reader (a loaded .ibt file)
@@ -65,3 +65,16 @@ func ReadDiskHeader(reader Reader) (*DiskHeader, error) {
 
 	return &h, nil
 }
+
+// EncodeDiskHeader creates the DiskHeader bytes of an ibt file, which are written directly after the TelemetryHeader.
+func EncodeDiskHeader(h *DiskHeader) []byte {
+	diskHeaderBuf := make([]byte, DISK_HEADER_BYTES_SIZE)
+
+	copy(diskHeaderBuf[0:8], utilities.Int64ToByte8(h.StartDate))
+	copy(diskHeaderBuf[8:16], utilities.FloatToByte8(h.StartTime))
+	copy(diskHeaderBuf[16:24], utilities.FloatToByte8(h.EndTime))
+	copy(diskHeaderBuf[24:28], utilities.IntToByte4(h.LapCount))
+	copy(diskHeaderBuf[28:32], utilities.IntToByte4(h.RecordCount))
+
+	return diskHeaderBuf
+}
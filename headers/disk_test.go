@@ -1,6 +1,7 @@
 package headers
 
 import (
+	"bytes"
 	"os"
 	"reflect"
 	"testing"
@@ -99,3 +100,13 @@ func TestDiskHeaders(t *testing.T) {
 		}
 	})
 }
+
+func TestEncodeDiskHeader(t *testing.T) {
+	t.Run("test EncodeDiskHeader matches testing file", func(t *testing.T) {
+		expected := readTestFileBytes(t, TELEMETRY_HEADER_BYTES_SIZE, TELEMETRY_HEADER_BYTES_SIZE+DISK_HEADER_BYTES_SIZE)
+
+		if output := EncodeDiskHeader(&expectedDiskHeader); !bytes.Equal(output, expected) {
+			t.Errorf("expected encoded disk header to be %v. received %v", expected, output)
+		}
+	})
+}
@@ -8,19 +8,32 @@ type Header struct {
 	DiskHeader      *DiskHeader
 	VarHeader       map[string]VarHeader
 	SessionInfo     *Session
-	VarBuffers      []VarBuffer
+	// Cleaned SessionInfo YAML, retaining the fields that are not part of Session
+	RawSessionInfo *RawSessionInfo
+	VarBuffers     []VarBuffer
 }
 
 // ParseHeader parses each of the required sub-headers of the ibt file in sequence.
-func ParseHeaders(r Reader) (*Header, error) {
+func ParseHeaders(r Reader) (*Header, error) { return parseHeaders(r, true) }
+
+// ParseLiveHeaders parses each of the sub-headers present in a live (memory-mapped) telemetry buffer.
+//
+// Live telemetry does not contain a DiskHeader, so the DiskHeader of the returned Header will be nil.
+func ParseLiveHeaders(r Reader) (*Header, error) { return parseHeaders(r, false) }
+
+// parseHeaders parses each of the sub-headers in sequence, only reading the DiskHeader when disk is true.
+func parseHeaders(r Reader, disk bool) (*Header, error) {
 	telemHeader, err := ReadTelemetryHeader(r)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse telemetry header: %v", err)
 	}
 
-	diskHeader, err := ReadDiskHeader(r)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse disk header: %v", err)
+	var diskHeader *DiskHeader
+	if disk {
+		diskHeader, err = ReadDiskHeader(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse disk header: %v", err)
+		}
 	}
 
 	varHeader, err := ReadVarHeader(r, telemHeader.NumVars, telemHeader.VarHeaderOffset)
@@ -33,7 +46,12 @@ func ParseHeaders(r Reader) (*Header, error) {
 		return nil, fmt.Errorf("failed to parse var buffer header: %v", err)
 	}
 
-	sessionInfo, err := ReadSessionInfo(r, telemHeader.SessionInfoOffset, telemHeader.SessionInfoLength)
+	rawSessionInfo, err := ReadRawSessionInfo(r, telemHeader.SessionInfoOffset, telemHeader.SessionInfoLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse session info: %v", err)
+	}
+
+	sessionInfo, err := rawSessionInfo.Session()
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse session info: %v", err)
 	}
@@ -43,10 +61,14 @@ func ParseHeaders(r Reader) (*Header, error) {
 		DiskHeader:      diskHeader,
 		VarHeader:       varHeader,
 		SessionInfo:     sessionInfo,
+		RawSessionInfo:  rawSessionInfo,
 		VarBuffers:      varBuffers,
 	}, nil
 }
 
+// UpdateVarBuffer re-reads the VarBuffer headers from the given Reader.
+//
+// This is used during live telemetry parsing, where the buffers are continuously rotated.
 func (h *Header) UpdateVarBuffer(r Reader) error {
 	varBuffers, err := ReadVarBufferHeaders(r, h.TelemetryHeader.NumBuf)
 	if err != nil {
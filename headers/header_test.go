@@ -48,6 +48,10 @@ func TestHeaders(t *testing.T) {
 		if !reflect.DeepEqual(expectedHeader.VarBuffers[0], output.VarBuffers[0]) {
 			t.Errorf("expected varBuffer header does not match actual. \nexpected: %+v\n \nactual: %+v\n", expectedHeader.VarBuffers[0], output.VarBuffers[0])
 		}
+
+		if value, ok := output.RawSessionInfo.Lookup("WeekendInfo.TrackName"); !ok || value != "spielberg gp" {
+			t.Errorf("expected raw session info to be retained. received track name %v", value)
+		}
 	})
 
 	t.Run("invalid header file", func(t *testing.T) {
@@ -224,6 +228,52 @@ func TestHeaders(t *testing.T) {
 	})
 }
 
+func TestParseLiveHeaders(t *testing.T) {
+	t.Run("live telemetry file", func(t *testing.T) {
+		f, err := os.Open("../.testing/live_test_file.ibt")
+		if err != nil {
+			t.Errorf("failed to open testing file - %v", err)
+			return
+		}
+		defer f.Close()
+
+		output, err := ParseLiveHeaders(f)
+		if err != nil {
+			t.Errorf("failed to parse live headers for testing file - %v", err)
+			return
+		}
+
+		if output.DiskHeader != nil {
+			t.Errorf("expected live headers to have no disk header. received: %+v", output.DiskHeader)
+		}
+
+		if output.TelemetryHeader.NumBuf != 3 {
+			t.Errorf("expected live headers to have %d buffers. received: %d", 3, output.TelemetryHeader.NumBuf)
+		}
+
+		if !reflect.DeepEqual(output.VarBuffers, expectedLiveVarBuffers) {
+			t.Errorf("expected live var buffers to be %v. received: %v", expectedLiveVarBuffers, output.VarBuffers)
+		}
+
+		if output.SessionInfo.WeekendInfo.TrackName != "monza full" {
+			t.Errorf("expected live session track to be %s. received: %s", "monza full", output.SessionInfo.WeekendInfo.TrackName)
+		}
+	})
+
+	t.Run("empty file", func(t *testing.T) {
+		f, err := os.Open("../.testing/empty_test_file.ibt")
+		if err != nil {
+			t.Errorf("failed to open testing file - %v", err)
+			return
+		}
+		defer f.Close()
+
+		if _, err := ParseLiveHeaders(f); err == nil {
+			t.Error("expected parsing of empty file to return an error")
+		}
+	})
+}
+
 func TestUpdateVarBuffer(t *testing.T) {
 	t.Run("test valid update var buffer", func(t *testing.T) {
 		validIbtFile, err := os.Open("../.testing/valid_test_file.ibt")
@@ -3,6 +3,8 @@ package headers
 import (
 	"bytes"
 	"fmt"
+	"os"
+	"testing"
 )
 
 // Shared resources for headers tests
@@ -37,3 +39,13 @@ func newMockReader(reader Reader, bufSize, start, end int) (*mockReader, error)
 }
 
 func (m mockReader) Close() error { return nil }
+
+// readTestFileBytes reads the given range of bytes of the valid testing file.
+func readTestFileBytes(t *testing.T, start, end int) []byte {
+	data, err := os.ReadFile("../.testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to read testing file - %v", err)
+	}
+
+	return data[start:end]
+}
@@ -0,0 +1,102 @@
+package headers
+
+import (
+	"errors"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RawSessionInfo is the cleaned SessionInfo YAML of an ibt file.
+//
+// Unlike Session, it retains every field written by iRacing, including fields that are not (yet) part of Session,
+// such as the tire compounds of the drivers or new fields added by iRacing. These fields can be read with Map and Lookup.
+type RawSessionInfo struct {
+	yaml string
+
+	// Generic view of the YAML, parsed once when it is first needed
+	once sync.Once
+	data map[string]interface{}
+	err  error
+}
+
+// NewRawSessionInfo creates a RawSessionInfo from the given cleaned YAML.
+func NewRawSessionInfo(rawYaml string) *RawSessionInfo {
+	return &RawSessionInfo{yaml: rawYaml}
+}
+
+// YAML returns the cleaned SessionInfo YAML.
+func (r *RawSessionInfo) YAML() string { return r.yaml }
+
+// Session parses the YAML into a Session.
+func (r *RawSessionInfo) Session() (*Session, error) {
+	output := Session{}
+
+	if err := yaml.Unmarshal([]byte(r.yaml), &output); err != nil {
+		return nil, err
+	}
+
+	if reflect.DeepEqual(output, Session{}) {
+		return nil, errors.New("unexpected empty session info found")
+	}
+
+	return &output, nil
+}
+
+// Map returns a generic view of the YAML, with mappings as maps and sequences as slices.
+//
+// The YAML is only parsed on the first call and the same map is returned afterwards, meaning it should not be modified.
+func (r *RawSessionInfo) Map() (map[string]interface{}, error) {
+	r.once.Do(func() {
+		r.data = make(map[string]interface{})
+		r.err = yaml.Unmarshal([]byte(r.yaml), &r.data)
+	})
+
+	return r.data, r.err
+}
+
+// Lookup returns the value at the given path of the YAML.
+//
+// The path consists of keys separated by dots, with indexes of sequences given in brackets or as keys. For example,
+// "WeekendInfo.TrackLength", "DriverInfo.Drivers[0].UserName", or "DriverInfo.Drivers.0.UserName".
+// False will be returned if the path does not exist.
+func (r *RawSessionInfo) Lookup(path string) (interface{}, bool) {
+	data, err := r.Map()
+	if err != nil || path == "" {
+		return nil, false
+	}
+
+	var value interface{} = data
+	for _, key := range splitLookupPath(path) {
+		var ok bool
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			value, ok = v[key]
+		case map[interface{}]interface{}:
+			value, ok = v[key]
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if ok = err == nil && idx >= 0 && idx < len(v); ok {
+				value = v[idx]
+			}
+		}
+
+		if !ok {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// splitLookupPath splits a path such as "Drivers[0].UserName" into it's keys "Drivers", "0", and "UserName".
+func splitLookupPath(path string) []string {
+	path = strings.ReplaceAll(path, "]", "")
+	path = strings.ReplaceAll(path, "[", ".")
+
+	return strings.Split(path, ".")
+}
@@ -0,0 +1,82 @@
+package headers
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+func TestRawSessionInfo(t *testing.T) {
+	f, err := os.Open("../.testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to open testing file - %v", err)
+	}
+	defer f.Close()
+
+	raw, err := ReadRawSessionInfo(f, expectedTelemetryHeader.SessionInfoOffset, expectedTelemetryHeader.SessionInfoLength)
+	if err != nil {
+		t.Fatalf("failed to read raw session info for testing file - %v", err)
+	}
+
+	t.Run("test RawSessionInfo Session", func(t *testing.T) {
+		session, err := raw.Session()
+		if err != nil {
+			t.Errorf("expected Session() to run without err. received error: %v", err)
+			return
+		}
+
+		if !reflect.DeepEqual(*session, expectedSessionInfo) {
+			t.Error("expected session to be equal to the parsed session info")
+		}
+
+		if _, err := NewRawSessionInfo("").Session(); err == nil {
+			t.Error("expected Session() to return an error for empty session info")
+		}
+	})
+
+	t.Run("test RawSessionInfo Map", func(t *testing.T) {
+		data, err := raw.Map()
+		if err != nil {
+			t.Errorf("expected Map() to run without err. received error: %v", err)
+			return
+		}
+
+		for _, key := range []string{"WeekendInfo", "SessionInfo", "CameraInfo", "RadioInfo", "DriverInfo", "SplitTimeInfo", "CarSetup"} {
+			if _, ok := data[key]; !ok {
+				t.Errorf("expected %s to be in the map", key)
+			}
+		}
+
+		if _, err := NewRawSessionInfo("WeekendInfo: [").Map(); err == nil {
+			t.Error("expected Map() to return an error for invalid yaml")
+		}
+	})
+
+	t.Run("test RawSessionInfo Lookup", func(t *testing.T) {
+		for path, expected := range map[string]interface{}{
+			"WeekendInfo.TrackLength":                      "4.28 km",
+			"WeekendInfo.TrackID":                          403,
+			"WeekendInfo.TrackPrecipitation":               "0 %",
+			"DriverInfo.Drivers[0].UserName":               "George v Rensburg",
+			"DriverInfo.Drivers.0.FaceType":                4,
+			"SplitTimeInfo.Sectors[1].SectorStartPct":      0.271918,
+			"CarSetup.TiresAero.TireCompound.TireCompound": "Medium",
+		} {
+			if value, ok := raw.Lookup(path); !ok || value != expected {
+				t.Errorf("expected %v at %s. received %v", expected, path, value)
+			}
+		}
+	})
+
+	t.Run("test RawSessionInfo Lookup missing", func(t *testing.T) {
+		for _, path := range []string{"", "Unknown", "WeekendInfo.Unknown", "DriverInfo.Drivers[5]", "DriverInfo.Drivers[x]", "WeekendInfo.TrackLength.Unit"} {
+			if value, ok := raw.Lookup(path); ok {
+				t.Errorf("expected %s to not be found. received %v", path, value)
+			}
+		}
+
+		if _, ok := NewRawSessionInfo("WeekendInfo: [").Lookup("WeekendInfo"); ok {
+			t.Error("expected Lookup() to return false for invalid yaml")
+		}
+	})
+}
@@ -1,8 +1,10 @@
 package headers
 
 import (
-	"errors"
+	"bytes"
+	"math"
 	"reflect"
+	"strconv"
 	"strings"
 
 	"golang.org/x/text/encoding/charmap"
@@ -12,7 +14,18 @@ import (
 // ReadSessionInfo extracts and parses the SessionInfo YAML from the given ibt file.
 //
 // Additional cleaning (mostly trimming some trailing bytes and spaces) is needed to ensure the YAML is correctly parsed.
+// Use ReadRawSessionInfo to retain the fields that are not part of Session.
 func ReadSessionInfo(reader Reader, offset, size int) (*Session, error) {
+	raw, err := ReadRawSessionInfo(reader, offset, size)
+	if err != nil {
+		return nil, err
+	}
+
+	return raw.Session()
+}
+
+// ReadRawSessionInfo extracts the cleaned SessionInfo YAML from the given ibt file without parsing it.
+func ReadRawSessionInfo(reader Reader, offset, size int) (*RawSessionInfo, error) {
 	sessionBuf := make([]byte, size)
 
 	_, err := reader.ReadAt(sessionBuf, int64(offset))
@@ -20,9 +33,14 @@ func ReadSessionInfo(reader Reader, offset, size int) (*Session, error) {
 		return nil, err
 	}
 
+	return DecodeSessionInfo(sessionBuf)
+}
+
+// DecodeSessionInfo decodes and cleans the Windows-1252 encoded SessionInfo YAML of an ibt file.
+func DecodeSessionInfo(sessionBuf []byte) (*RawSessionInfo, error) {
 	dec := charmap.Windows1252.NewDecoder()
 
-	sessionBuf, err = dec.Bytes(sessionBuf)
+	sessionBuf, err := dec.Bytes(sessionBuf)
 	if err != nil {
 		return nil, err
 	}
@@ -31,17 +49,180 @@ func ReadSessionInfo(reader Reader, offset, size int) (*Session, error) {
 	rawYaml = strings.TrimRight(rawYaml, ".")
 	rawYaml = strings.TrimSpace(rawYaml)
 
-	output := Session{}
+	return NewRawSessionInfo(rawYaml), nil
+}
+
+// EncodeSessionInfo creates the SessionInfo YAML of an ibt file from the given session.
+//
+// The YAML is encoded in Windows-1252 and surrounded by the document markers used by iRacing.
+// Characters that cannot be represented in Windows-1252 are replaced with a question mark.
+func EncodeSessionInfo(session *Session) ([]byte, error) {
+	return EncodeMergedSessionInfo(session, nil)
+}
 
-	if err := yaml.Unmarshal([]byte(rawYaml), &output); err != nil {
+// EncodeMergedSessionInfo creates the SessionInfo YAML of an ibt file from the given session merged over the raw YAML.
+//
+// The values of the session replace those of the raw YAML, while fields of the raw YAML that are not part of
+// Session are preserved in their original order. A nil raw YAML encodes the session in the same manner as
+// EncodeSessionInfo.
+func EncodeMergedSessionInfo(session *Session, raw *RawSessionInfo) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(wrapUntypedFloats(reflect.ValueOf(session)).Interface()); err != nil {
 		return nil, err
 	}
 
-	if reflect.DeepEqual(output, Session{}) {
-		return nil, errors.New("unexpected empty session info found")
+	if raw != nil {
+		var rawNode yaml.Node
+		if err := yaml.Unmarshal([]byte(raw.YAML()), &rawNode); err != nil {
+			return nil, err
+		}
+
+		if len(rawNode.Content) > 0 {
+			node = *mergeNodes(rawNode.Content[0], &node)
+		}
+	}
+
+	var buf bytes.Buffer
+	buf.WriteString("---\n")
+
+	enc := yaml.NewEncoder(&buf)
+	// The encoder does not support the single space indentation used by iRacing
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return nil, err
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	buf.WriteString("\n...\n")
+
+	// Replace characters that cannot be represented, since the replacement byte of the encoder is not valid YAML
+	sessionInfo := strings.Map(func(r rune) rune {
+		if _, ok := charmap.Windows1252.EncodeRune(r); ok {
+			return r
+		}
+		return '?'
+	}, buf.String())
+
+	return charmap.Windows1252.NewEncoder().Bytes([]byte(sessionInfo))
+}
+
+// mergeNodes merges the values of src over dst and returns the merged node.
+//
+// Mappings are merged by key, keeping the keys of dst that are missing from src, and sequences are merged
+// by index. Any other value of src replaces the value of dst.
+func mergeNodes(dst, src *yaml.Node) *yaml.Node {
+	if dst.Kind != src.Kind {
+		return src
+	}
+
+	switch src.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+
+			found := false
+			for j := 0; j+1 < len(dst.Content); j += 2 {
+				if dst.Content[j].Value == key.Value {
+					dst.Content[j+1] = mergeNodes(dst.Content[j+1], value)
+					found = true
+					break
+				}
+			}
+
+			if !found {
+				dst.Content = append(dst.Content, key, value)
+			}
+		}
+
+		return dst
+	case yaml.SequenceNode:
+		for i, value := range src.Content {
+			if i < len(dst.Content) {
+				dst.Content[i] = mergeNodes(dst.Content[i], value)
+			} else {
+				dst.Content = append(dst.Content, value)
+			}
+		}
+		dst.Content = dst.Content[:len(src.Content)]
+
+		return dst
+	}
+
+	return src
+}
+
+// yamlFloat is a float that is always encoded with a decimal point, such as -1.0 instead of -1.
+type yamlFloat float64
+
+func (f yamlFloat) MarshalYAML() (interface{}, error) {
+	if math.IsNaN(float64(f)) || math.IsInf(float64(f), 0) {
+		return float64(f), nil
+	}
+
+	value := strconv.FormatFloat(float64(f), 'f', -1, 64)
+	if !strings.Contains(value, ".") {
+		value += ".0"
+	}
+
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!float", Value: value}, nil
+}
+
+// wrapUntypedFloats returns a copy of the value where every float64 stored in an interface is a yamlFloat.
+//
+// Untyped values, such as the ResultsPositions of a session, would otherwise be read back as ints when a
+// whole float is encoded without a decimal point.
+func wrapUntypedFloats(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Ptr:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Elem().Type())
+		copied.Elem().Set(wrapUntypedFloats(v.Elem()))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.NumField(); i++ {
+			if copied.Field(i).CanSet() {
+				copied.Field(i).Set(wrapUntypedFloats(v.Field(i)))
+			}
+		}
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(wrapUntypedFloats(v.Index(i)))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			copied.SetMapIndex(iter.Key(), wrapUntypedFloats(iter.Value()))
+		}
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		inner := wrapUntypedFloats(v.Elem())
+		if inner.Kind() == reflect.Float64 {
+			inner = reflect.ValueOf(yamlFloat(inner.Float()))
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(inner)
+		return copied
 	}
 
-	return &output, nil
+	return v
 }
 
 // GetDriver attempts to find the current driver's information from the SessionInfo object.
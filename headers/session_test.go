@@ -1,8 +1,11 @@
 package headers
 
 import (
+	"bytes"
 	"os"
 	"reflect"
+	"sort"
+	"strings"
 	"testing"
 )
 
@@ -1117,3 +1120,138 @@ var expectedSessionInfo = Session{
 		},
 	},
 }
+
+func TestEncodeSessionInfo(t *testing.T) {
+	t.Run("test EncodeSessionInfo round trip", func(t *testing.T) {
+		output, err := EncodeSessionInfo(&expectedSessionInfo)
+		if err != nil {
+			t.Errorf("expected EncodeSessionInfo() to run without err. received error: %v", err)
+			return
+		}
+
+		if !bytes.HasPrefix(output, []byte("---\n")) || !bytes.HasSuffix(output, []byte("\n...\n")) {
+			t.Errorf("expected session info to have document markers. received %s", output)
+		}
+
+		decoded, err := ReadSessionInfo(&mockReader{bytes.NewReader(output)}, 0, len(output))
+		if err != nil {
+			t.Errorf("expected encoded session info to be read without err. received error: %v", err)
+			return
+		}
+
+		if !reflect.DeepEqual(*decoded, expectedSessionInfo) {
+			t.Errorf("expected encoded session info to be equal to the original. \nexpected: %+v\n \nactual: %+v\n", expectedSessionInfo, *decoded)
+		}
+	})
+
+	t.Run("test EncodeSessionInfo Windows-1252", func(t *testing.T) {
+		session := expectedSessionInfo
+		session.WeekendInfo.TrackCity = "Spielberg – Österreich ✓"
+
+		output, err := EncodeSessionInfo(&session)
+		if err != nil {
+			t.Errorf("expected EncodeSessionInfo() to run without err. received error: %v", err)
+			return
+		}
+
+		decoded, err := ReadSessionInfo(&mockReader{bytes.NewReader(output)}, 0, len(output))
+		if err != nil {
+			t.Errorf("expected encoded session info to be read without err. received error: %v", err)
+			return
+		}
+
+		// The check mark cannot be represented in Windows-1252 and is replaced
+		if decoded.WeekendInfo.TrackCity != "Spielberg – Österreich ?" {
+			t.Errorf("expected track city to be encoded in Windows-1252. received %q", decoded.WeekendInfo.TrackCity)
+		}
+	})
+}
+
+func TestEncodeMergedSessionInfo(t *testing.T) {
+	f, err := os.Open("../.testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to open testing file - %v", err)
+	}
+	defer f.Close()
+
+	raw, err := ReadRawSessionInfo(f, expectedTelemetryHeader.SessionInfoOffset, expectedTelemetryHeader.SessionInfoLength)
+	if err != nil {
+		t.Fatalf("failed to read raw session info for testing file - %v", err)
+	}
+
+	t.Run("test EncodeMergedSessionInfo preserves fields", func(t *testing.T) {
+		session := expectedSessionInfo
+		session.WeekendInfo.TrackCity = "Graz"
+
+		output, err := EncodeMergedSessionInfo(&session, raw)
+		if err != nil {
+			t.Errorf("expected EncodeMergedSessionInfo() to run without err. received error: %v", err)
+			return
+		}
+
+		merged, err := DecodeSessionInfo(output)
+		if err != nil {
+			t.Errorf("expected merged session info to be decoded without err. received error: %v", err)
+			return
+		}
+
+		decoded, err := merged.Session()
+		if err != nil {
+			t.Errorf("expected merged session info to be parsed without err. received error: %v", err)
+			return
+		}
+
+		if !reflect.DeepEqual(*decoded, session) {
+			t.Error("expected merged session info to be equal to the session")
+		}
+
+		for path, expected := range map[string]interface{}{
+			"WeekendInfo.TrackCity":          "Graz",
+			"WeekendInfo.TrackPrecipitation": "0 %",
+			"DriverInfo.Drivers[0].FaceType": 4,
+		} {
+			if value, ok := merged.Lookup(path); !ok || value != expected {
+				t.Errorf("expected %v at %s. received %v", expected, path, value)
+			}
+		}
+
+		// The order of the original YAML is preserved
+		order := []int{
+			strings.Index(merged.YAML(), "\nWeekendInfo:"),
+			strings.Index(merged.YAML(), "\nSessionInfo:"),
+			strings.Index(merged.YAML(), "\nCameraInfo:"),
+			strings.Index(merged.YAML(), "\nCarSetup:"),
+		}
+		if !sort.IntsAreSorted(order) || order[0] != 3 {
+			t.Errorf("expected the original order of fields. received positions %v", order)
+		}
+	})
+
+	t.Run("test EncodeMergedSessionInfo sequences", func(t *testing.T) {
+		session := expectedSessionInfo
+		session.DriverInfo.Drivers = append([]Drivers{}, session.DriverInfo.Drivers...)
+		session.DriverInfo.Drivers = append(session.DriverInfo.Drivers, Drivers{CarIdx: 1, UserName: "Second"})
+		session.SplitTimeInfo.Sectors = session.SplitTimeInfo.Sectors[:1]
+
+		output, err := EncodeMergedSessionInfo(&session, raw)
+		if err != nil {
+			t.Errorf("expected EncodeMergedSessionInfo() to run without err. received error: %v", err)
+			return
+		}
+
+		merged, _ := DecodeSessionInfo(output)
+		decoded, err := merged.Session()
+		if err != nil {
+			t.Errorf("expected merged session info to be parsed without err. received error: %v", err)
+			return
+		}
+
+		if !reflect.DeepEqual(*decoded, session) {
+			t.Error("expected merged session info to be equal to the session")
+		}
+
+		if _, ok := merged.Lookup("DriverInfo.Drivers[1].FaceType"); ok {
+			t.Error("expected added driver to only contain the fields of the session")
+		}
+	})
+}
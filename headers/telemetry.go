@@ -12,30 +12,30 @@ const (
 
 type TelemetryHeader struct {
 	// Version of ibt file
-	Version int
+	Version int `json:"version"`
 	// Status indicates whether session is live (0) or completed (1)
-	Status int
+	Status int `json:"status"`
 	// Tickrate indicates the frequency of telemetry data written to the file.
 	// A value of 60 indicates 60 times per second
-	TickRate int
+	TickRate int `json:"tick_rate"`
 	// Indicates the number of times SessionInfo was updated for the current file.
 	// The value will be 0 for completed sessions and >1 for active sessions
-	SessionInfoUpdate int
+	SessionInfoUpdate int `json:"session_info_update"`
 	// Buffer offset for SessionInfo data
-	SessionInfoOffset int
+	SessionInfoOffset int `json:"session_info_offset"`
 	// Length of the SessionInfo buffer
-	SessionInfoLength int
+	SessionInfoLength int `json:"session_info_length"`
 	// Number of available telemetry vars that will be written at the Tickrate frequency
-	NumVars int
+	NumVars int `json:"num_vars"`
 	// Buffer offset for VarHeader
-	VarHeaderOffset int
+	VarHeaderOffset int `json:"var_header_offset"`
 	// Specifies the number of telemetry data buffers available.
 	// This will be 1 for ibt files and 3 for memory-mapped live telemetry
-	NumBuf int
+	NumBuf int `json:"num_buf"`
 	// Length of the buffer for parsing VarHeader telemetry values
-	BufLen int
+	BufLen int `json:"buf_len"`
 	// Buffer offset for the VarHeader telemetry values
-	BufOffset int
+	BufOffset int `json:"buf_offset"`
 }
 
 // ReadTelemetryHeader attempts to parse the TelemetryHeader from the given Reader (a loaded .ibt file)
@@ -69,3 +69,34 @@ func ReadTelemetryHeader(reader Reader) (*TelemetryHeader, error) {
 
 	return &h, nil
 }
+
+// EncodeTelemetryHeader creates the TelemetryHeader bytes of an ibt file, including the headers of the given VarBuffers.
+//
+// The BufOffset of the TelemetryHeader is read from the first VarBuffer, meaning it should be the BufOffset
+// of varBuffers[0]. A maximum of 4 VarBuffers can be encoded.
+func EncodeTelemetryHeader(h *TelemetryHeader, varBuffers []VarBuffer) ([]byte, error) {
+	if len(varBuffers) > 4 {
+		return nil, fmt.Errorf("a maximum of 4 var buffers can be encoded. received %d", len(varBuffers))
+	}
+
+	headerBuf := make([]byte, TELEMETRY_HEADER_BYTES_SIZE)
+
+	copy(headerBuf[0:4], utilities.IntToByte4(h.Version))
+	copy(headerBuf[4:8], utilities.IntToByte4(h.Status))
+	copy(headerBuf[8:12], utilities.IntToByte4(h.TickRate))
+	copy(headerBuf[12:16], utilities.IntToByte4(h.SessionInfoUpdate))
+	copy(headerBuf[16:20], utilities.IntToByte4(h.SessionInfoLength))
+	copy(headerBuf[20:24], utilities.IntToByte4(h.SessionInfoOffset))
+	copy(headerBuf[24:28], utilities.IntToByte4(h.NumVars))
+	copy(headerBuf[28:32], utilities.IntToByte4(h.VarHeaderOffset))
+	copy(headerBuf[32:36], utilities.IntToByte4(h.NumBuf))
+	copy(headerBuf[36:40], utilities.IntToByte4(h.BufLen))
+
+	for i, vb := range varBuffers {
+		start := VAR_BUFFER_HEADER_BASE_OFFSET + i*VAR_BUFFER_INCREMENT
+		copy(headerBuf[start:start+4], utilities.IntToByte4(vb.TickCount))
+		copy(headerBuf[start+4:start+8], utilities.IntToByte4(vb.BufOffset))
+	}
+
+	return headerBuf, nil
+}
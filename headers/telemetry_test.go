@@ -1,6 +1,7 @@
 package headers
 
 import (
+	"bytes"
 	"os"
 	"reflect"
 	"testing"
@@ -68,3 +69,25 @@ func TestTelemetryHeaders(t *testing.T) {
 		}
 	})
 }
+
+func TestEncodeTelemetryHeader(t *testing.T) {
+	t.Run("test EncodeTelemetryHeader matches testing file", func(t *testing.T) {
+		expected := readTestFileBytes(t, 0, TELEMETRY_HEADER_BYTES_SIZE)
+		varBuffers := []VarBuffer{{TickCount: 54661, BufOffset: 53764}}
+
+		output, err := EncodeTelemetryHeader(&expectedTelemetryHeader, varBuffers)
+		if err != nil {
+			t.Errorf("expected EncodeTelemetryHeader() to run without err. received error: %v", err)
+		}
+
+		if !bytes.Equal(output, expected) {
+			t.Errorf("expected encoded telemetry header to be %v. received %v", expected, output)
+		}
+	})
+
+	t.Run("test EncodeTelemetryHeader too many var buffers", func(t *testing.T) {
+		if _, err := EncodeTelemetryHeader(&expectedTelemetryHeader, make([]VarBuffer, 5)); err == nil {
+			t.Error("expected EncodeTelemetryHeader() to return an error for more than 4 var buffers")
+		}
+	})
+}
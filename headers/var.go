@@ -2,6 +2,7 @@ package headers
 
 import (
 	"fmt"
+	"sort"
 	"unicode/utf8"
 
 	"github.com/teamjorge/ibt/utilities"
@@ -20,7 +21,7 @@ type VarHeader struct {
 	// 0: Uint8
 	// 1: Boolean
 	// 2: Int
-	// 3: String
+	// 3: Bitfield (uint32)
 	// 4: Float32
 	// 5: Float64
 	Rtype int `json:"rtype,omitempty"`
@@ -37,7 +38,7 @@ type VarHeader struct {
 	Unit string `json:"unit,omitempty"`
 
 	// Value of the variable. The is parsed during iteration of telemetry data.
-	Value interface{} `json:"value"`
+	Value interface{} `json:"value,omitempty"`
 }
 
 // ReadVarHeader populates the the VarHeader with the necessary metadata.
@@ -86,3 +87,37 @@ func ReadVarHeader(reader Reader, numVars, offset int) (map[string]VarHeader, er
 func AvailableVars(varHeaders map[string]VarHeader) []string {
 	return maps.Keys(varHeaders)
 }
+
+// EncodeVarHeaders creates the VarHeader bytes of an ibt file for the given variables.
+//
+// Variables are written in the order of their Offset in the telemetry buffer. Names, descriptions, and units
+// that are too long for their fields are truncated.
+func EncodeVarHeaders(varHeaders map[string]VarHeader) []byte {
+	vars := maps.Values(varHeaders)
+	sort.Slice(vars, func(i, j int) bool {
+		if vars[i].Offset == vars[j].Offset {
+			return vars[i].Name < vars[j].Name
+		}
+		return vars[i].Offset < vars[j].Offset
+	})
+
+	varHeaderBuf := make([]byte, len(vars)*VAR_HEADER_BYTES_SIZE)
+
+	start := 0
+	for _, h := range vars {
+		copy(varHeaderBuf[start+0:start+4], utilities.IntToByte4(h.Rtype))
+		copy(varHeaderBuf[start+4:start+8], utilities.IntToByte4(h.Offset))
+		copy(varHeaderBuf[start+8:start+12], utilities.IntToByte4(h.Count))
+		if h.CountAsTime {
+			varHeaderBuf[start+12] = 1
+		}
+		// Padded
+		copy(varHeaderBuf[start+16:start+48], utilities.StringToBytes(h.Name, 32))
+		copy(varHeaderBuf[start+48:start+112], utilities.StringToBytes(h.Description, 64))
+		copy(varHeaderBuf[start+112:start+144], utilities.StringToBytes(h.Unit, 32))
+
+		start += VAR_HEADER_BYTES_SIZE
+	}
+
+	return varHeaderBuf
+}
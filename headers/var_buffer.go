@@ -13,8 +13,8 @@ const (
 
 // VarBuffer is a header providing information on each of the available live data buffers
 type VarBuffer struct {
-	TickCount int
-	BufOffset int
+	TickCount int `json:"tick_count"`
+	BufOffset int `json:"buf_offset"`
 }
 
 // ParseVarBufferHeader retrieves the metadata of available live data buffers.
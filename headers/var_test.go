@@ -1,6 +1,7 @@
 package headers
 
 import (
+	"bytes"
 	"os"
 	"reflect"
 	"sort"
@@ -2851,3 +2852,45 @@ var expectedVarHeaders = map[string]VarHeader{
 		Value:       nil,
 	},
 }
+
+func TestEncodeVarHeaders(t *testing.T) {
+	f, err := os.Open("../.testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to open testing file - %v", err)
+		return
+	}
+	defer f.Close()
+
+	varHeaders, err := ReadVarHeader(f, expectedTelemetryHeader.NumVars, expectedTelemetryHeader.VarHeaderOffset)
+	if err != nil {
+		t.Errorf("failed to parse var headers for testing file - %v", err)
+		return
+	}
+
+	t.Run("test EncodeVarHeaders round trip", func(t *testing.T) {
+		output := EncodeVarHeaders(varHeaders)
+
+		if len(output) != expectedTelemetryHeader.NumVars*VAR_HEADER_BYTES_SIZE {
+			t.Errorf("expected %d bytes. received %d", expectedTelemetryHeader.NumVars*VAR_HEADER_BYTES_SIZE, len(output))
+			return
+		}
+
+		decoded, err := ReadVarHeader(&mockReader{bytes.NewReader(output)}, expectedTelemetryHeader.NumVars, 0)
+		if err != nil {
+			t.Errorf("expected encoded var headers to be read without err. received error: %v", err)
+		}
+
+		if !reflect.DeepEqual(decoded, varHeaders) {
+			t.Error("expected encoded var headers to be equal to the testing file")
+		}
+	})
+
+	t.Run("test EncodeVarHeaders ordered by offset", func(t *testing.T) {
+		output := EncodeVarHeaders(varHeaders)
+
+		name := string(bytes.TrimRight(output[16:48], "\x00"))
+		if name != "SessionTime" {
+			t.Errorf("expected the first var to be %s. received %s", "SessionTime", name)
+		}
+	})
+}
@@ -0,0 +1,351 @@
+// Package irsdk provides typed values for the bitfield and enumerated telemetry variables of the iRacing SDK.
+//
+// The types and their values are based on the definitions of irsdk_defines.h.
+package irsdk
+
+import (
+	"fmt"
+	"strings"
+)
+
+// BitField is implemented by all typed irsdk bitfields.
+type BitField interface {
+	// Raw value of the bitfield
+	Raw() uint32
+	// Names of all bits that are set
+	Names() []string
+	// String of all bits that are set, separated by a "|"
+	String() string
+}
+
+// bitName is the name of a single bit of a bitfield
+type bitName struct {
+	mask uint32
+	name string
+}
+
+// bitNames returns the names of the set bits of value. Unknown bits are named by their hex value.
+func bitNames(value uint32, known []bitName) []string {
+	names := make([]string, 0)
+
+	remaining := value
+	for _, bit := range known {
+		if value&bit.mask != 0 {
+			names = append(names, bit.name)
+			remaining &^= bit.mask
+		}
+	}
+
+	for i := 0; i < 32; i++ {
+		if mask := uint32(1) << i; remaining&mask != 0 {
+			names = append(names, fmt.Sprintf("0x%x", mask))
+		}
+	}
+
+	return names
+}
+
+// bitString joins the names of the set bits of value
+func bitString(value uint32, known []bitName) string {
+	if value == 0 {
+		return "none"
+	}
+
+	return strings.Join(bitNames(value, known), "|")
+}
+
+// SessionFlags are the flags of the session and driver (irsdk_Flags), found in the SessionFlags variable.
+type SessionFlags uint32
+
+// Global flags
+const (
+	Checkered     SessionFlags = 0x00000001
+	White         SessionFlags = 0x00000002
+	Green         SessionFlags = 0x00000004
+	Yellow        SessionFlags = 0x00000008
+	Red           SessionFlags = 0x00000010
+	Blue          SessionFlags = 0x00000020
+	Debris        SessionFlags = 0x00000040
+	Crossed       SessionFlags = 0x00000080
+	YellowWaving  SessionFlags = 0x00000100
+	OneLapToGreen SessionFlags = 0x00000200
+	GreenHeld     SessionFlags = 0x00000400
+	TenToGo       SessionFlags = 0x00000800
+	FiveToGo      SessionFlags = 0x00001000
+	RandomWaving  SessionFlags = 0x00002000
+	Caution       SessionFlags = 0x00004000
+	CautionWaving SessionFlags = 0x00008000
+)
+
+// Driver black flags
+const (
+	Black            SessionFlags = 0x00010000
+	Disqualify       SessionFlags = 0x00020000
+	Servicible       SessionFlags = 0x00040000
+	Furled           SessionFlags = 0x00080000
+	Repair           SessionFlags = 0x00100000
+	DQScoringInvalid SessionFlags = 0x00200000
+)
+
+// Start lights
+const (
+	StartHidden SessionFlags = 0x10000000
+	StartReady  SessionFlags = 0x20000000
+	StartSet    SessionFlags = 0x40000000
+	StartGo     SessionFlags = 0x80000000
+)
+
+var sessionFlagNames = []bitName{
+	{uint32(Checkered), "Checkered"},
+	{uint32(White), "White"},
+	{uint32(Green), "Green"},
+	{uint32(Yellow), "Yellow"},
+	{uint32(Red), "Red"},
+	{uint32(Blue), "Blue"},
+	{uint32(Debris), "Debris"},
+	{uint32(Crossed), "Crossed"},
+	{uint32(YellowWaving), "YellowWaving"},
+	{uint32(OneLapToGreen), "OneLapToGreen"},
+	{uint32(GreenHeld), "GreenHeld"},
+	{uint32(TenToGo), "TenToGo"},
+	{uint32(FiveToGo), "FiveToGo"},
+	{uint32(RandomWaving), "RandomWaving"},
+	{uint32(Caution), "Caution"},
+	{uint32(CautionWaving), "CautionWaving"},
+	{uint32(Black), "Black"},
+	{uint32(Disqualify), "Disqualify"},
+	{uint32(Servicible), "Servicible"},
+	{uint32(Furled), "Furled"},
+	{uint32(Repair), "Repair"},
+	{uint32(DQScoringInvalid), "DQScoringInvalid"},
+	{uint32(StartHidden), "StartHidden"},
+	{uint32(StartReady), "StartReady"},
+	{uint32(StartSet), "StartSet"},
+	{uint32(StartGo), "StartGo"},
+}
+
+// Has returns true if all of the given flags are set
+func (f SessionFlags) Has(flags SessionFlags) bool { return f&flags == flags }
+
+// Raw value of the flags
+func (f SessionFlags) Raw() uint32 { return uint32(f) }
+
+// Names of all flags that are set
+func (f SessionFlags) Names() []string { return bitNames(uint32(f), sessionFlagNames) }
+
+// String of all flags that are set
+func (f SessionFlags) String() string { return bitString(uint32(f), sessionFlagNames) }
+
+// EngineWarnings are the engine warnings (irsdk_EngineWarnings), found in the EngineWarnings variable.
+type EngineWarnings uint32
+
+const (
+	WaterTempWarning    EngineWarnings = 0x0001
+	FuelPressureWarning EngineWarnings = 0x0002
+	OilPressureWarning  EngineWarnings = 0x0004
+	EngineStalled       EngineWarnings = 0x0008
+	PitSpeedLimiter     EngineWarnings = 0x0010
+	RevLimiterActive    EngineWarnings = 0x0020
+	OilTempWarning      EngineWarnings = 0x0040
+	MandRepNeeded       EngineWarnings = 0x0080
+	OptRepNeeded        EngineWarnings = 0x0100
+)
+
+var engineWarningNames = []bitName{
+	{uint32(WaterTempWarning), "WaterTempWarning"},
+	{uint32(FuelPressureWarning), "FuelPressureWarning"},
+	{uint32(OilPressureWarning), "OilPressureWarning"},
+	{uint32(EngineStalled), "EngineStalled"},
+	{uint32(PitSpeedLimiter), "PitSpeedLimiter"},
+	{uint32(RevLimiterActive), "RevLimiterActive"},
+	{uint32(OilTempWarning), "OilTempWarning"},
+	{uint32(MandRepNeeded), "MandRepNeeded"},
+	{uint32(OptRepNeeded), "OptRepNeeded"},
+}
+
+// Has returns true if all of the given warnings are set
+func (e EngineWarnings) Has(warnings EngineWarnings) bool { return e&warnings == warnings }
+
+// Raw value of the warnings
+func (e EngineWarnings) Raw() uint32 { return uint32(e) }
+
+// Names of all warnings that are set
+func (e EngineWarnings) Names() []string { return bitNames(uint32(e), engineWarningNames) }
+
+// String of all warnings that are set
+func (e EngineWarnings) String() string { return bitString(uint32(e), engineWarningNames) }
+
+// CameraState is the state of the camera tool (irsdk_CameraState), found in the CamCameraState variable.
+type CameraState uint32
+
+const (
+	IsSessionScreen       CameraState = 0x0001
+	IsScenicActive        CameraState = 0x0002
+	CamToolActive         CameraState = 0x0004
+	UIHidden              CameraState = 0x0008
+	UseAutoShotSelection  CameraState = 0x0010
+	UseTemporaryEdits     CameraState = 0x0020
+	UseKeyAcceleration    CameraState = 0x0040
+	UseKey10xAcceleration CameraState = 0x0080
+	UseMouseAimMode       CameraState = 0x0100
+)
+
+var cameraStateNames = []bitName{
+	{uint32(IsSessionScreen), "IsSessionScreen"},
+	{uint32(IsScenicActive), "IsScenicActive"},
+	{uint32(CamToolActive), "CamToolActive"},
+	{uint32(UIHidden), "UIHidden"},
+	{uint32(UseAutoShotSelection), "UseAutoShotSelection"},
+	{uint32(UseTemporaryEdits), "UseTemporaryEdits"},
+	{uint32(UseKeyAcceleration), "UseKeyAcceleration"},
+	{uint32(UseKey10xAcceleration), "UseKey10xAcceleration"},
+	{uint32(UseMouseAimMode), "UseMouseAimMode"},
+}
+
+// Has returns true if all of the given states are set
+func (c CameraState) Has(state CameraState) bool { return c&state == state }
+
+// Raw value of the state
+func (c CameraState) Raw() uint32 { return uint32(c) }
+
+// Names of all states that are set
+func (c CameraState) Names() []string { return bitNames(uint32(c), cameraStateNames) }
+
+// String of all states that are set
+func (c CameraState) String() string { return bitString(uint32(c), cameraStateNames) }
+
+// PitSvFlags are the pit services requested (irsdk_PitSvFlags), found in the PitSvFlags variable.
+type PitSvFlags uint32
+
+const (
+	LFTireChange      PitSvFlags = 0x0001
+	RFTireChange      PitSvFlags = 0x0002
+	LRTireChange      PitSvFlags = 0x0004
+	RRTireChange      PitSvFlags = 0x0008
+	FuelFill          PitSvFlags = 0x0010
+	WindshieldTearoff PitSvFlags = 0x0020
+	FastRepair        PitSvFlags = 0x0040
+)
+
+var pitSvFlagNames = []bitName{
+	{uint32(LFTireChange), "LFTireChange"},
+	{uint32(RFTireChange), "RFTireChange"},
+	{uint32(LRTireChange), "LRTireChange"},
+	{uint32(RRTireChange), "RRTireChange"},
+	{uint32(FuelFill), "FuelFill"},
+	{uint32(WindshieldTearoff), "WindshieldTearoff"},
+	{uint32(FastRepair), "FastRepair"},
+}
+
+// Has returns true if all of the given services are set
+func (p PitSvFlags) Has(flags PitSvFlags) bool { return p&flags == flags }
+
+// Raw value of the services
+func (p PitSvFlags) Raw() uint32 { return uint32(p) }
+
+// Names of all services that are set
+func (p PitSvFlags) Names() []string { return bitNames(uint32(p), pitSvFlagNames) }
+
+// String of all services that are set
+func (p PitSvFlags) String() string { return bitString(uint32(p), pitSvFlagNames) }
+
+// PaceFlags are the pacing flags of a car (irsdk_PaceFlags), found in the CarIdxPaceFlags variable.
+type PaceFlags uint32
+
+const (
+	EndOfLine   PaceFlags = 0x0001
+	FreePass    PaceFlags = 0x0002
+	WavedAround PaceFlags = 0x0004
+)
+
+var paceFlagNames = []bitName{
+	{uint32(EndOfLine), "EndOfLine"},
+	{uint32(FreePass), "FreePass"},
+	{uint32(WavedAround), "WavedAround"},
+}
+
+// Has returns true if all of the given flags are set
+func (p PaceFlags) Has(flags PaceFlags) bool { return p&flags == flags }
+
+// Raw value of the flags
+func (p PaceFlags) Raw() uint32 { return uint32(p) }
+
+// Names of all flags that are set
+func (p PaceFlags) Names() []string { return bitNames(uint32(p), paceFlagNames) }
+
+// String of all flags that are set
+func (p PaceFlags) String() string { return bitString(uint32(p), paceFlagNames) }
+
+// GenericBitField is used for bitfield variables without a known type.
+//
+// The names of set bits are given as their hex values.
+type GenericBitField uint32
+
+// Has returns true if all of the given bits are set
+func (g GenericBitField) Has(bits GenericBitField) bool { return g&bits == bits }
+
+// Raw value of the bitfield
+func (g GenericBitField) Raw() uint32 { return uint32(g) }
+
+// Names of all bits that are set
+func (g GenericBitField) Names() []string { return bitNames(uint32(g), nil) }
+
+// String of all bits that are set
+func (g GenericBitField) String() string { return bitString(uint32(g), nil) }
+
+// bitFieldDecoder creates typed bitfields for a single variable
+type bitFieldDecoder struct {
+	value  func(raw uint32) BitField
+	values func(raw []uint32) interface{}
+}
+
+func newBitFieldDecoder[T interface {
+	~uint32
+	BitField
+}]() bitFieldDecoder {
+	return bitFieldDecoder{
+		value: func(raw uint32) BitField { return T(raw) },
+		values: func(raw []uint32) interface{} {
+			values := make([]T, len(raw))
+			for i := range raw {
+				values[i] = T(raw[i])
+			}
+			return values
+		},
+	}
+}
+
+// bitFieldVars is the registry of known bitfield variables by name
+var bitFieldVars = map[string]bitFieldDecoder{
+	"SessionFlags":       newBitFieldDecoder[SessionFlags](),
+	"CarIdxSessionFlags": newBitFieldDecoder[SessionFlags](),
+	"EngineWarnings":     newBitFieldDecoder[EngineWarnings](),
+	"CamCameraState":     newBitFieldDecoder[CameraState](),
+	"PitSvFlags":         newBitFieldDecoder[PitSvFlags](),
+	"CarIdxPaceFlags":    newBitFieldDecoder[PaceFlags](),
+}
+
+var genericBitFieldDecoder = newBitFieldDecoder[GenericBitField]()
+
+// DecodeBitField returns the typed bitfield for the variable with the given name.
+//
+// A GenericBitField is returned for variables that are not known.
+func DecodeBitField(name string, raw uint32) BitField {
+	if decoder, ok := bitFieldVars[name]; ok {
+		return decoder.value(raw)
+	}
+
+	return genericBitFieldDecoder.value(raw)
+}
+
+// DecodeBitFields returns a typed slice of bitfields for the array variable with the given name.
+//
+// For example, CarIdxPaceFlags will be returned as a []PaceFlags. A []GenericBitField is returned
+// for variables that are not known.
+func DecodeBitFields(name string, raw []uint32) interface{} {
+	if decoder, ok := bitFieldVars[name]; ok {
+		return decoder.values(raw)
+	}
+
+	return genericBitFieldDecoder.values(raw)
+}
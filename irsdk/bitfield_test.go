@@ -0,0 +1,100 @@
+package irsdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSessionFlags(t *testing.T) {
+	flags := SessionFlags(0x10040200)
+
+	t.Run("test SessionFlags Has", func(t *testing.T) {
+		if !flags.Has(OneLapToGreen) || !flags.Has(Servicible|StartHidden) {
+			t.Errorf("expected %s to have OneLapToGreen, Servicible and StartHidden set", flags)
+		}
+
+		if flags.Has(Checkered) || flags.Has(Checkered|StartHidden) {
+			t.Errorf("expected %s to not have Checkered set", flags)
+		}
+	})
+
+	t.Run("test SessionFlags Names", func(t *testing.T) {
+		expected := []string{"OneLapToGreen", "Servicible", "StartHidden"}
+		if !reflect.DeepEqual(flags.Names(), expected) {
+			t.Errorf("expected names to be %v. received %v", expected, flags.Names())
+		}
+
+		if flags.String() != "OneLapToGreen|Servicible|StartHidden" {
+			t.Errorf("expected string to be %s. received %s", "OneLapToGreen|Servicible|StartHidden", flags.String())
+		}
+
+		if flags.Raw() != 0x10040200 {
+			t.Errorf("expected raw value to be %#x. received %#x", 0x10040200, flags.Raw())
+		}
+	})
+
+	t.Run("test SessionFlags unknown bits", func(t *testing.T) {
+		unknown := Green | SessionFlags(0x01000000)
+
+		if unknown.String() != "Green|0x1000000" {
+			t.Errorf("expected unknown bits to be named by their hex value. received %s", unknown.String())
+		}
+
+		if SessionFlags(0).String() != "none" || len(SessionFlags(0).Names()) != 0 {
+			t.Errorf("expected no flags to be set. received %v", SessionFlags(0).Names())
+		}
+	})
+}
+
+func TestBitFieldTypes(t *testing.T) {
+	tests := []struct {
+		value    BitField
+		expected string
+	}{
+		{EngineWarnings(0x30), "PitSpeedLimiter|RevLimiterActive"},
+		{CameraState(0x9), "IsSessionScreen|UIHidden"},
+		{PitSvFlags(0x1f), "LFTireChange|RFTireChange|LRTireChange|RRTireChange|FuelFill"},
+		{PaceFlags(0x6), "FreePass|WavedAround"},
+		{GenericBitField(0x5), "0x1|0x4"},
+	}
+
+	for _, test := range tests {
+		if test.value.String() != test.expected {
+			t.Errorf("expected %T to be %s. received %s", test.value, test.expected, test.value.String())
+		}
+	}
+
+	if !EngineWarnings(0x30).Has(PitSpeedLimiter) || !CameraState(0x9).Has(UIHidden) ||
+		!PitSvFlags(0x1f).Has(FuelFill) || !PaceFlags(0x6).Has(WavedAround) || !GenericBitField(0x5).Has(0x4) {
+		t.Error("expected Has() to return true for set bits")
+	}
+}
+
+func TestDecodeBitField(t *testing.T) {
+	t.Run("test DecodeBitField known variables", func(t *testing.T) {
+		tests := map[string]BitField{
+			"SessionFlags":   SessionFlags(1),
+			"EngineWarnings": EngineWarnings(1),
+			"CamCameraState": CameraState(1),
+			"PitSvFlags":     PitSvFlags(1),
+			"Unknown":        GenericBitField(1),
+		}
+
+		for name, expected := range tests {
+			if value := DecodeBitField(name, 1); value != expected {
+				t.Errorf("expected %s to be decoded as %T. received %T", name, expected, value)
+			}
+		}
+	})
+
+	t.Run("test DecodeBitFields", func(t *testing.T) {
+		values := DecodeBitFields("CarIdxPaceFlags", []uint32{1, 2})
+		if !reflect.DeepEqual(values, []PaceFlags{EndOfLine, FreePass}) {
+			t.Errorf("expected CarIdxPaceFlags to be decoded as []PaceFlags. received %v of type %T", values, values)
+		}
+
+		if _, ok := DecodeBitFields("Unknown", []uint32{1}).([]GenericBitField); !ok {
+			t.Error("expected unknown variables to be decoded as []GenericBitField")
+		}
+	})
+}
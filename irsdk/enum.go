@@ -0,0 +1,316 @@
+package irsdk
+
+import "fmt"
+
+// Enum is implemented by all typed irsdk enums.
+type Enum interface {
+	// Raw value of the enum
+	Raw() int
+	// String is the name of the enum value
+	String() string
+}
+
+// enumString returns the name of the value, or the type and value when it is not known.
+func enumString(typeName string, value int, names map[int]string) string {
+	if name, ok := names[value]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("%s(%d)", typeName, value)
+}
+
+// TrkLoc is the location of a car relative to the track (irsdk_TrkLoc), found in the PlayerTrackSurface
+// and CarIdxTrackSurface variables.
+type TrkLoc int
+
+const (
+	TrkLocNotInWorld      TrkLoc = -1
+	TrkLocOffTrack        TrkLoc = 0
+	TrkLocInPitStall      TrkLoc = 1
+	TrkLocApproachingPits TrkLoc = 2
+	TrkLocOnTrack         TrkLoc = 3
+)
+
+var trkLocNames = map[int]string{
+	-1: "NotInWorld",
+	0:  "OffTrack",
+	1:  "InPitStall",
+	2:  "ApproachingPits",
+	3:  "OnTrack",
+}
+
+// IsOnTrack returns true if the car is on the racing surface
+func (t TrkLoc) IsOnTrack() bool { return t == TrkLocOnTrack }
+
+// InPitStall returns true if the car is in it's pit stall
+func (t TrkLoc) InPitStall() bool { return t == TrkLocInPitStall }
+
+// InWorld returns true if the car is in the world. Cars that are not in the world are usually in the garage.
+func (t TrkLoc) InWorld() bool { return t != TrkLocNotInWorld }
+
+// Raw value of the location
+func (t TrkLoc) Raw() int { return int(t) }
+
+// String is the name of the location
+func (t TrkLoc) String() string { return enumString("TrkLoc", int(t), trkLocNames) }
+
+// TrkSurf is the material of the surface below a car (irsdk_TrkSurf), found in the PlayerTrackSurfaceMaterial
+// and CarIdxTrackSurfaceMaterial variables.
+type TrkSurf int
+
+const (
+	SurfaceNotInWorld   TrkSurf = -1
+	UndefinedMaterial   TrkSurf = 0
+	Asphalt1Material    TrkSurf = 1
+	Asphalt2Material    TrkSurf = 2
+	Asphalt3Material    TrkSurf = 3
+	Asphalt4Material    TrkSurf = 4
+	Concrete1Material   TrkSurf = 5
+	Concrete2Material   TrkSurf = 6
+	RacingDirt1Material TrkSurf = 7
+	RacingDirt2Material TrkSurf = 8
+	Paint1Material      TrkSurf = 9
+	Paint2Material      TrkSurf = 10
+	Rumble1Material     TrkSurf = 11
+	Rumble2Material     TrkSurf = 12
+	Rumble3Material     TrkSurf = 13
+	Rumble4Material     TrkSurf = 14
+	Grass1Material      TrkSurf = 15
+	Grass2Material      TrkSurf = 16
+	Grass3Material      TrkSurf = 17
+	Grass4Material      TrkSurf = 18
+	Dirt1Material       TrkSurf = 19
+	Dirt2Material       TrkSurf = 20
+	Dirt3Material       TrkSurf = 21
+	Dirt4Material       TrkSurf = 22
+	SandMaterial        TrkSurf = 23
+	Gravel1Material     TrkSurf = 24
+	Gravel2Material     TrkSurf = 25
+	GrasscreteMaterial  TrkSurf = 26
+	AstroturfMaterial   TrkSurf = 27
+)
+
+var trkSurfNames = map[int]string{
+	-1: "SurfaceNotInWorld",
+	0:  "UndefinedMaterial",
+	1:  "Asphalt1Material",
+	2:  "Asphalt2Material",
+	3:  "Asphalt3Material",
+	4:  "Asphalt4Material",
+	5:  "Concrete1Material",
+	6:  "Concrete2Material",
+	7:  "RacingDirt1Material",
+	8:  "RacingDirt2Material",
+	9:  "Paint1Material",
+	10: "Paint2Material",
+	11: "Rumble1Material",
+	12: "Rumble2Material",
+	13: "Rumble3Material",
+	14: "Rumble4Material",
+	15: "Grass1Material",
+	16: "Grass2Material",
+	17: "Grass3Material",
+	18: "Grass4Material",
+	19: "Dirt1Material",
+	20: "Dirt2Material",
+	21: "Dirt3Material",
+	22: "Dirt4Material",
+	23: "SandMaterial",
+	24: "Gravel1Material",
+	25: "Gravel2Material",
+	26: "GrasscreteMaterial",
+	27: "AstroturfMaterial",
+}
+
+// Raw value of the surface
+func (t TrkSurf) Raw() int { return int(t) }
+
+// String is the name of the surface
+func (t TrkSurf) String() string { return enumString("TrkSurf", int(t), trkSurfNames) }
+
+// SessionState is the state of the current session (irsdk_SessionState), found in the SessionState variable.
+type SessionState int
+
+const (
+	SessionStateInvalid    SessionState = 0
+	SessionStateGetInCar   SessionState = 1
+	SessionStateWarmup     SessionState = 2
+	SessionStateParadeLaps SessionState = 3
+	SessionStateRacing     SessionState = 4
+	SessionStateCheckered  SessionState = 5
+	SessionStateCoolDown   SessionState = 6
+)
+
+var sessionStateNames = map[int]string{
+	0: "Invalid",
+	1: "GetInCar",
+	2: "Warmup",
+	3: "ParadeLaps",
+	4: "Racing",
+	5: "Checkered",
+	6: "CoolDown",
+}
+
+// Raw value of the state
+func (s SessionState) Raw() int { return int(s) }
+
+// String is the name of the state
+func (s SessionState) String() string { return enumString("SessionState", int(s), sessionStateNames) }
+
+// PitSvStatus is the status of the requested pit services (irsdk_PitSvStatus), found in the
+// PlayerCarPitSvStatus variable.
+type PitSvStatus int
+
+const (
+	PitSvNone       PitSvStatus = 0
+	PitSvInProgress PitSvStatus = 1
+	PitSvComplete   PitSvStatus = 2
+
+	// Errors
+	PitSvTooFarLeft    PitSvStatus = 100
+	PitSvTooFarRight   PitSvStatus = 101
+	PitSvTooFarForward PitSvStatus = 102
+	PitSvTooFarBack    PitSvStatus = 103
+	PitSvBadAngle      PitSvStatus = 104
+	PitSvCantFixThat   PitSvStatus = 105
+)
+
+var pitSvStatusNames = map[int]string{
+	0:   "None",
+	1:   "InProgress",
+	2:   "Complete",
+	100: "TooFarLeft",
+	101: "TooFarRight",
+	102: "TooFarForward",
+	103: "TooFarBack",
+	104: "BadAngle",
+	105: "CantFixThat",
+}
+
+// IsError returns true if the car is not positioned correctly for pit services
+func (p PitSvStatus) IsError() bool { return p >= PitSvTooFarLeft }
+
+// Raw value of the status
+func (p PitSvStatus) Raw() int { return int(p) }
+
+// String is the name of the status
+func (p PitSvStatus) String() string { return enumString("PitSvStatus", int(p), pitSvStatusNames) }
+
+// CarLeftRight is the spotter indication of cars alongside (irsdk_CarLeftRight), found in the CarLeftRight variable.
+type CarLeftRight int
+
+const (
+	LROff          CarLeftRight = 0
+	LRClear        CarLeftRight = 1
+	LRCarLeft      CarLeftRight = 2
+	LRCarRight     CarLeftRight = 3
+	LRCarLeftRight CarLeftRight = 4
+	LR2CarsLeft    CarLeftRight = 5
+	LR2CarsRight   CarLeftRight = 6
+)
+
+var carLeftRightNames = map[int]string{
+	0: "Off",
+	1: "Clear",
+	2: "CarLeft",
+	3: "CarRight",
+	4: "CarLeftRight",
+	5: "2CarsLeft",
+	6: "2CarsRight",
+}
+
+// Raw value of the indication
+func (c CarLeftRight) Raw() int { return int(c) }
+
+// String is the name of the indication
+func (c CarLeftRight) String() string { return enumString("CarLeftRight", int(c), carLeftRightNames) }
+
+// PaceMode is the pacing mode of the session (irsdk_PaceMode), found in the PaceMode variable.
+type PaceMode int
+
+const (
+	PaceModeSingleFileStart   PaceMode = 0
+	PaceModeDoubleFileStart   PaceMode = 1
+	PaceModeSingleFileRestart PaceMode = 2
+	PaceModeDoubleFileRestart PaceMode = 3
+	PaceModeNotPacing         PaceMode = 4
+)
+
+var paceModeNames = map[int]string{
+	0: "SingleFileStart",
+	1: "DoubleFileStart",
+	2: "SingleFileRestart",
+	3: "DoubleFileRestart",
+	4: "NotPacing",
+}
+
+// Raw value of the mode
+func (p PaceMode) Raw() int { return int(p) }
+
+// String is the name of the mode
+func (p PaceMode) String() string { return enumString("PaceMode", int(p), paceModeNames) }
+
+// enumDecoder creates typed enums for a single variable
+type enumDecoder struct {
+	value  func(raw int) Enum
+	values func(raw []int) interface{}
+}
+
+func newEnumDecoder[T interface {
+	~int
+	Enum
+}]() enumDecoder {
+	return enumDecoder{
+		value: func(raw int) Enum { return T(raw) },
+		values: func(raw []int) interface{} {
+			values := make([]T, len(raw))
+			for i := range raw {
+				values[i] = T(raw[i])
+			}
+			return values
+		},
+	}
+}
+
+// enumVars is the registry of known enum variables by name
+var enumVars = map[string]enumDecoder{
+	"PlayerTrackSurface":         newEnumDecoder[TrkLoc](),
+	"CarIdxTrackSurface":         newEnumDecoder[TrkLoc](),
+	"PlayerTrackSurfaceMaterial": newEnumDecoder[TrkSurf](),
+	"CarIdxTrackSurfaceMaterial": newEnumDecoder[TrkSurf](),
+	"SessionState":               newEnumDecoder[SessionState](),
+	"PlayerCarPitSvStatus":       newEnumDecoder[PitSvStatus](),
+	"CarLeftRight":               newEnumDecoder[CarLeftRight](),
+	"PaceMode":                   newEnumDecoder[PaceMode](),
+}
+
+// IsEnum returns true if the variable with the given name is a known enum.
+func IsEnum(name string) bool {
+	_, ok := enumVars[name]
+	return ok
+}
+
+// DecodeEnum returns the typed enum for the variable with the given name.
+//
+// False is returned when the variable is not a known enum.
+func DecodeEnum(name string, raw int) (Enum, bool) {
+	decoder, ok := enumVars[name]
+	if !ok {
+		return nil, false
+	}
+
+	return decoder.value(raw), true
+}
+
+// DecodeEnums returns a typed slice of enums for the array variable with the given name.
+//
+// For example, CarIdxTrackSurface will be returned as a []TrkLoc. False is returned when the variable
+// is not a known enum.
+func DecodeEnums(name string, raw []int) (interface{}, bool) {
+	decoder, ok := enumVars[name]
+	if !ok {
+		return nil, false
+	}
+
+	return decoder.values(raw), true
+}
@@ -0,0 +1,88 @@
+package irsdk
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTrkLoc(t *testing.T) {
+	if !TrkLocOnTrack.IsOnTrack() || TrkLocInPitStall.IsOnTrack() {
+		t.Error("expected only OnTrack to be on track")
+	}
+
+	if !TrkLocInPitStall.InPitStall() || TrkLocApproachingPits.InPitStall() {
+		t.Error("expected only InPitStall to be in the pit stall")
+	}
+
+	if TrkLocNotInWorld.InWorld() || !TrkLocOffTrack.InWorld() {
+		t.Error("expected only NotInWorld to not be in the world")
+	}
+}
+
+func TestEnumString(t *testing.T) {
+	tests := []struct {
+		value    Enum
+		expected string
+	}{
+		{TrkLocNotInWorld, "NotInWorld"},
+		{TrkLocApproachingPits, "ApproachingPits"},
+		{TrkLoc(42), "TrkLoc(42)"},
+		{AstroturfMaterial, "AstroturfMaterial"},
+		{SessionStateCheckered, "Checkered"},
+		{PitSvCantFixThat, "CantFixThat"},
+		{LR2CarsLeft, "2CarsLeft"},
+		{PaceModeNotPacing, "NotPacing"},
+		{PaceMode(-1), "PaceMode(-1)"},
+	}
+
+	for _, test := range tests {
+		if test.value.String() != test.expected {
+			t.Errorf("expected %T %d to be %s. received %s", test.value, test.value.Raw(), test.expected, test.value.String())
+		}
+	}
+
+	if PitSvComplete.IsError() || !PitSvBadAngle.IsError() {
+		t.Error("expected only pit service statuses from 100 to be errors")
+	}
+}
+
+func TestDecodeEnum(t *testing.T) {
+	t.Run("test DecodeEnum known variables", func(t *testing.T) {
+		tests := map[string]Enum{
+			"PlayerTrackSurface":         TrkLocOnTrack,
+			"PlayerTrackSurfaceMaterial": Asphalt3Material,
+			"SessionState":               SessionStateParadeLaps,
+			"PlayerCarPitSvStatus":       PitSvStatus(3),
+			"CarLeftRight":               LRCarRight,
+			"PaceMode":                   PaceModeDoubleFileRestart,
+		}
+
+		for name, expected := range tests {
+			value, ok := DecodeEnum(name, 3)
+			if !ok || value != expected {
+				t.Errorf("expected %s to be decoded as %T %v. received %T %v", name, expected, expected, value, value)
+			}
+
+			if !IsEnum(name) {
+				t.Errorf("expected %s to be an enum", name)
+			}
+		}
+	})
+
+	t.Run("test DecodeEnum unknown variable", func(t *testing.T) {
+		if _, ok := DecodeEnum("Gear", 3); ok || IsEnum("Gear") {
+			t.Error("expected Gear to not be an enum")
+		}
+
+		if _, ok := DecodeEnums("CarIdxGear", []int{3}); ok {
+			t.Error("expected CarIdxGear to not be an enum")
+		}
+	})
+
+	t.Run("test DecodeEnums", func(t *testing.T) {
+		values, ok := DecodeEnums("CarIdxTrackSurface", []int{-1, 3})
+		if !ok || !reflect.DeepEqual(values, []TrkLoc{TrkLocNotInWorld, TrkLocOnTrack}) {
+			t.Errorf("expected CarIdxTrackSurface to be decoded as []TrkLoc. received %v of type %T", values, values)
+		}
+	})
+}
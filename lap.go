@@ -0,0 +1,108 @@
+package ibt
+
+import (
+	"fmt"
+
+	"github.com/teamjorge/ibt/metric"
+)
+
+// Lap is the tick range of a single lap within an ibt file.
+type Lap struct {
+	// Lap number, as given by the Lap variable
+	Number int
+	// Index of the first tick of the lap
+	Start int
+	// Index of the tick after the last tick of the lap
+	End int
+	// Duration of the lap. For incomplete laps, this is only the duration that was recorded.
+	Time metric.LapTime
+	// Whether the lap was started on pit road
+	OutLap bool
+	// Whether the lap was ended on pit road
+	InLap bool
+	// Whether the whole lap was recorded. Laps that are cut off by the start or end of the file are incomplete.
+	Complete bool
+}
+
+// Len is the number of ticks in the lap
+func (l Lap) Len() int { return l.End - l.Start }
+
+// SplitLaps splits the ticks of a stub into laps based on changes to the Lap variable.
+//
+// Lap times are calculated with the SessionTime variable. The first and last laps of the stub are
+// cut off at the StartTime and EndTime of the file and will therefore never be complete.
+//
+// The tick ranges of the returned laps can be used with Parser.SeekLap to process a single lap.
+func SplitLaps(stub Stub) ([]Lap, error) {
+	if stub.header == nil || stub.header.DiskHeader == nil {
+		return nil, fmt.Errorf("stub %s does not have the headers of an ibt file", stub.Filename())
+	}
+
+	vars := []string{"Lap", "SessionTime"}
+	if _, ok := stub.header.VarHeader["OnPitRoad"]; ok {
+		vars = append(vars, "OnPitRoad")
+	}
+
+	frame, err := LoadChannels(stub, vars...)
+	if err != nil {
+		return nil, err
+	}
+
+	laps, err := GetColumn[[]int](frame, "Lap")
+	if err != nil {
+		return nil, fmt.Errorf("failed to split laps for stub %s: %v", stub.Filename(), err)
+	}
+
+	sessionTime, err := GetColumn[[]float64](frame, "SessionTime")
+	if err != nil {
+		return nil, fmt.Errorf("failed to split laps for stub %s: %v", stub.Filename(), err)
+	}
+
+	// OnPitRoad will be nil when the variable is not available
+	onPitRoad, _ := GetColumn[[]bool](frame, "OnPitRoad")
+
+	disk := stub.header.DiskHeader
+
+	return splitLaps(laps, onPitRoad, sessionTime, disk.StartTime, disk.EndTime), nil
+}
+
+// splitLaps creates a Lap for each range of ticks with the same lap number.
+func splitLaps(laps []int, onPitRoad []bool, sessionTime []float64, startTime, endTime float64) []Lap {
+	result := make([]Lap, 0)
+
+	start := 0
+	for i := 1; i <= len(laps); i++ {
+		if i < len(laps) && laps[i] == laps[start] {
+			continue
+		}
+
+		lap := Lap{
+			Number:   laps[start],
+			Start:    start,
+			End:      i,
+			Complete: start > 0 && i < len(laps),
+		}
+
+		lapStart := startTime
+		if start > 0 {
+			lapStart = sessionTime[start]
+		}
+
+		lapEnd := endTime
+		if i < len(laps) {
+			lapEnd = sessionTime[i]
+		}
+
+		lap.Time = metric.LapTime(lapEnd - lapStart)
+
+		if onPitRoad != nil {
+			lap.OutLap = onPitRoad[start]
+			lap.InLap = onPitRoad[i-1]
+		}
+
+		result = append(result, lap)
+		start = i
+	}
+
+	return result
+}
@@ -0,0 +1,90 @@
+package ibt
+
+import (
+	"testing"
+
+	"github.com/teamjorge/ibt/metric"
+)
+
+func TestSplitLaps(t *testing.T) {
+	stubs, err := ParseStubs(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to parse stubs for testing file - %v", err)
+		return
+	}
+	defer stubs.Close()
+
+	t.Run("test SplitLaps without disk header", func(t *testing.T) {
+		header := *stubs[0].Headers()
+		header.DiskHeader = nil
+
+		if _, err := SplitLaps(Stub{filepath: stubs[0].Filename(), header: &header, r: stubs[0].r}); err == nil {
+			t.Error("expected SplitLaps() to return an error for a stub without a disk header")
+		}
+	})
+
+	t.Run("test SplitLaps single lap", func(t *testing.T) {
+		laps, err := SplitLaps(stubs[0])
+		if err != nil {
+			t.Errorf("expected SplitLaps() to run without err. received error: %v", err)
+			return
+		}
+
+		if len(laps) != 1 {
+			t.Errorf("expected %d lap. received %d", 1, len(laps))
+			return
+		}
+
+		lap := laps[0]
+		if lap.Number != 9 || lap.Start != 0 || lap.End != 390 || lap.Len() != 390 {
+			t.Errorf("expected lap 9 to cover ticks 0 to 390. received %+v", lap)
+		}
+
+		if lap.Complete || !lap.OutLap || !lap.InLap {
+			t.Errorf("expected lap to be an incomplete out and in lap. received %+v", lap)
+		}
+
+		if lap.Time.ToString() != "00:06.483" {
+			t.Errorf("expected lap time to be %s. received %s", "00:06.483", lap.Time.ToString())
+		}
+	})
+
+	t.Run("test splitLaps multiple laps", func(t *testing.T) {
+		laps := []int{1, 1, 2, 2, 2, 3}
+		onPitRoad := []bool{true, false, false, false, true, true}
+		sessionTime := []float64{10, 20, 30, 40, 50, 60}
+
+		result := splitLaps(laps, onPitRoad, sessionTime, 5, 65)
+
+		expected := []Lap{
+			{Number: 1, Start: 0, End: 2, Time: metric.LapTime(25), OutLap: true},
+			{Number: 2, Start: 2, End: 5, Time: metric.LapTime(30), InLap: true, Complete: true},
+			{Number: 3, Start: 5, End: 6, Time: metric.LapTime(5), OutLap: true, InLap: true},
+		}
+
+		if len(result) != len(expected) {
+			t.Errorf("expected %d laps. received %d", len(expected), len(result))
+			return
+		}
+
+		for i := range expected {
+			if result[i] != expected[i] {
+				t.Errorf("expected lap %d to be %+v. received %+v", i, expected[i], result[i])
+			}
+		}
+	})
+
+	t.Run("test splitLaps without OnPitRoad", func(t *testing.T) {
+		result := splitLaps([]int{1, 2}, nil, []float64{1, 2}, 1, 2)
+
+		if len(result) != 2 || result[0].OutLap || result[1].InLap {
+			t.Errorf("expected 2 laps without pit road information. received %+v", result)
+		}
+	})
+
+	t.Run("test splitLaps no ticks", func(t *testing.T) {
+		if result := splitLaps(nil, nil, nil, 0, 0); len(result) != 0 {
+			t.Errorf("expected no laps. received %+v", result)
+		}
+	})
+}
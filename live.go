@@ -0,0 +1,173 @@
+package ibt
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/utilities/fifo"
+)
+
+const (
+	// Number of recent tick counts remembered when de-duplicating live telemetry buffers
+	liveSeenTicksSize int = 30
+)
+
+// LiveParser is used to iterate telemetry ticks from a live (memory-mapped) telemetry buffer.
+//
+// Live telemetry rotates between multiple var buffers (see headers.TelemetryHeader.NumBuf). Each poll
+// will re-read the buffer headers, drop any ticks that have already been seen and return the new ticks
+// in the order they were written.
+type LiveParser struct {
+	reader headers.Reader
+	header *headers.Header
+	parser *Parser
+
+	seen fifo.Store[int]
+	last int
+}
+
+// NewLiveParser creates a new live parser from the given live telemetry reader, it's headers, and a variable whitelist.
+//
+// reader - Live telemetry reader. For example, a memory-mapped file or a captured copy of one.
+//
+// header - Parsed headers of the live telemetry. See headers.ParseLiveHeaders.
+//
+// whitelist - Variables to process. For example, "gear", "speed", "rpm" etc.
+func NewLiveParser(reader headers.Reader, header *headers.Header, whitelist ...string) *LiveParser {
+	lp := new(LiveParser)
+
+	lp.reader = reader
+	lp.header = header
+	lp.parser = NewParser(reader, header, whitelist...)
+
+	lp.seen = fifo.NewStore[int](liveSeenTicksSize)
+	lp.last = -1
+
+	return lp
+}
+
+// Poll the live var buffers and return all ticks that have not been seen before.
+//
+// Returned ticks are ordered by their tick count, with the freshest tick last. Buffers containing a tick
+// older than the last returned tick are dropped to ensure ticks are never returned out of order.
+//
+// Since iRacing may overwrite a buffer while it is being read, the buffer headers are read again after every
+// buffer. Ticks of which the buffer was overwritten in the meantime are dropped and read by the next poll instead.
+func (lp *LiveParser) Poll() ([]Tick, error) {
+	if err := lp.header.UpdateVarBuffer(lp.reader); err != nil {
+		return nil, err
+	}
+
+	// Indexes of the buffers, ordered by their tick count
+	order := make([]int, len(lp.header.VarBuffers))
+	for i := range order {
+		order[i] = i
+	}
+	buffers := lp.header.VarBuffers
+	sort.Slice(order, func(i, j int) bool { return buffers[order[i]].TickCount < buffers[order[j]].TickCount })
+
+	ticks := make([]Tick, 0)
+	for _, idx := range order {
+		buffer := buffers[idx]
+		if buffer.TickCount <= lp.last || lp.seen.Exists(buffer.TickCount) {
+			continue
+		}
+
+		tick := lp.parser.ParseAt(buffer.BufOffset)
+		if tick == nil {
+			return ticks, fmt.Errorf("failed to read live buffer for tick %d at offset %d", buffer.TickCount, buffer.BufOffset)
+		}
+
+		current, err := headers.ReadVarBufferHeaders(lp.reader, lp.header.TelemetryHeader.NumBuf)
+		if err != nil {
+			return ticks, err
+		}
+
+		// The buffer was overwritten while it was being read
+		if current[idx].TickCount != buffer.TickCount {
+			continue
+		}
+
+		lp.seen.Add(buffer.TickCount)
+		lp.last = buffer.TickCount
+		ticks = append(ticks, tick)
+	}
+
+	return ticks, nil
+}
+
+// UpdateWhitelist replaces the current whitelist with the given fields
+func (lp *LiveParser) UpdateWhitelist(whitelist ...string) { lp.parser.UpdateWhitelist(whitelist...) }
+
+// UseLegacyBitFields configures the live parser to read bitfield variables as hex strings. See Parser.UseLegacyBitFields.
+func (lp *LiveParser) UseLegacyBitFields(enabled bool) { lp.parser.UseLegacyBitFields(enabled) }
+
+// UseEnums configures the live parser to read known enum variables as typed irsdk enums. See Parser.UseEnums.
+func (lp *LiveParser) UseEnums(enabled bool) { lp.parser.UseEnums(enabled) }
+
+// LiveOptions configures how live telemetry is processed.
+type LiveOptions struct {
+	// Interval at which the live telemetry is polled. Defaults to the TickRate of the telemetry.
+	Interval time.Duration
+	// Read bitfields as hex strings, such as "0x10040200", instead of typed irsdk bitfields.
+	// See Parser.UseLegacyBitFields.
+	LegacyBitFields bool
+	// Read known enum variables, such as PlayerTrackSurface, as typed irsdk enums instead of ints.
+	// See Parser.UseEnums.
+	Enums bool
+}
+
+// ProcessLive polls the given live telemetry reader and passes every new tick to the given processors.
+//
+// Polling happens at the given interval. If the interval is 0 or less, the TickRate of the telemetry will be used,
+// and an error is returned when the TickRate is not set.
+// Processing continues until the context is done, after which the error of the context is returned, wrapped with
+// the last tick count that was processed. Use errors.Is to check for context.Canceled or context.DeadlineExceeded.
+// Since live telemetry has no end, hasNext will always be true for the processors.
+func ProcessLive(ctx context.Context, reader headers.Reader, header *headers.Header, interval time.Duration, processors ...Processor) error {
+	return ProcessLiveWithOptions(ctx, reader, header, LiveOptions{Interval: interval}, processors...)
+}
+
+// ProcessLiveWithOptions processes live telemetry in the same manner as ProcessLive, using the given options.
+func ProcessLiveWithOptions(ctx context.Context, reader headers.Reader, header *headers.Header, opts LiveOptions, processors ...Processor) error {
+	interval := opts.Interval
+	if interval <= 0 {
+		if header.TelemetryHeader.TickRate <= 0 {
+			return fmt.Errorf("an interval is required for live telemetry with a tick rate of %d", header.TelemetryHeader.TickRate)
+		}
+		interval = time.Second / time.Duration(header.TelemetryHeader.TickRate)
+	}
+
+	whitelist := buildWhitelist(header.VarHeader, processors...)
+
+	lp := NewLiveParser(reader, header, whitelist...)
+	lp.UseLegacyBitFields(opts.LegacyBitFields)
+	lp.UseEnums(opts.Enums)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		ticks, err := lp.Poll()
+		if err != nil {
+			return err
+		}
+
+		for _, tick := range ticks {
+			for _, proc := range processors {
+				if err := proc.Process(tick.Filter(proc.Whitelist()...), true, header.SessionInfo); err != nil {
+					return err
+				}
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("processing of live telemetry stopped at tick %d: %w", lp.last, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,196 @@
+package ibt
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
+)
+
+// overwritingReader simulates iRacing overwriting the first var buffer while it is being read.
+type overwritingReader struct {
+	data      []byte
+	bufOffset int
+	bufLen    int
+	// Whether the buffer has been overwritten
+	overwritten bool
+}
+
+func (r *overwritingReader) ReadAt(p []byte, off int64) (int, error) {
+	n := copy(p, r.data[off:])
+
+	if int(off) == r.bufOffset && len(p) == r.bufLen && !r.overwritten {
+		r.overwritten = true
+		// Increase the tick count of the first buffer, as if a new tick was written to it
+		tickCount := r.data[headers.VAR_BUFFER_HEADER_BASE_OFFSET : headers.VAR_BUFFER_HEADER_BASE_OFFSET+4]
+		binary.LittleEndian.PutUint32(tickCount, binary.LittleEndian.Uint32(tickCount)+100)
+	}
+
+	return n, nil
+}
+
+func (r *overwritingReader) Read(p []byte) (int, error) { return 0, io.EOF }
+
+func (r *overwritingReader) Close() error { return nil }
+
+func TestLiveParser(t *testing.T) {
+	f, err := os.Open(".testing/live_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to open testing file - %v", err)
+		return
+	}
+	defer f.Close()
+
+	testHeaders, err := headers.ParseLiveHeaders(f)
+	if err != nil {
+		t.Errorf("failed to parse live header for testing file - %v", err)
+		return
+	}
+
+	t.Run("test LiveParser Poll() ordered ticks", func(t *testing.T) {
+		lp := NewLiveParser(f, testHeaders, "SessionTick")
+
+		ticks, err := lp.Poll()
+		if err != nil {
+			t.Errorf("expected Poll() to run without err. received error: %v", err)
+		}
+
+		expectedTicks := []int{3441, 3442, 3443}
+		if len(ticks) != len(expectedTicks) {
+			t.Errorf("expected %d ticks to be polled. received %d", len(expectedTicks), len(ticks))
+			return
+		}
+
+		for idx, expectedTick := range expectedTicks {
+			if ticks[idx]["SessionTick"] != expectedTick {
+				t.Errorf("expected tick %d to have a SessionTick of %d. received %v", idx, expectedTick, ticks[idx]["SessionTick"])
+			}
+		}
+	})
+
+	t.Run("test LiveParser Poll() drops seen ticks", func(t *testing.T) {
+		lp := NewLiveParser(f, testHeaders, "SessionTick")
+
+		if _, err := lp.Poll(); err != nil {
+			t.Errorf("expected Poll() to run without err. received error: %v", err)
+		}
+
+		ticks, err := lp.Poll()
+		if err != nil {
+			t.Errorf("expected Poll() to run without err. received error: %v", err)
+		}
+
+		if len(ticks) != 0 {
+			t.Errorf("expected no new ticks to be polled. received %v", ticks)
+		}
+	})
+
+	t.Run("test LiveParser Poll() overwritten buffer", func(t *testing.T) {
+		data, err := os.ReadFile(".testing/live_test_file.ibt")
+		if err != nil {
+			t.Fatalf("failed to read testing file - %v", err)
+		}
+
+		overwritten := NewParser(f, testHeaders, "SessionTick").ParseAt(testHeaders.VarBuffers[0].BufOffset)["SessionTick"]
+
+		header := *testHeaders
+		reader := &overwritingReader{data: data, bufOffset: testHeaders.VarBuffers[0].BufOffset, bufLen: testHeaders.TelemetryHeader.BufLen}
+		lp := NewLiveParser(reader, &header, "SessionTick")
+
+		ticks, err := lp.Poll()
+		if err != nil {
+			t.Errorf("expected Poll() to run without err. received error: %v", err)
+		}
+
+		for _, tick := range ticks {
+			if tick["SessionTick"] == overwritten {
+				t.Errorf("expected the tick of the overwritten buffer to be dropped. received %v", ticks)
+			}
+		}
+
+		if len(ticks) != len(testHeaders.VarBuffers)-1 {
+			t.Errorf("expected %d ticks to be polled. received %d", len(testHeaders.VarBuffers)-1, len(ticks))
+		}
+	})
+
+	t.Run("test LiveParser Poll() invalid buffer", func(t *testing.T) {
+		empty, err := os.Open(".testing/empty_test_file.ibt")
+		if err != nil {
+			t.Errorf("failed to open testing file - %v", err)
+			return
+		}
+		defer empty.Close()
+
+		lp := NewLiveParser(empty, &headers.Header{TelemetryHeader: testHeaders.TelemetryHeader}, "SessionTick")
+
+		if _, err := lp.Poll(); err == nil {
+			t.Error("expected Poll() to return an error for an empty buffer")
+		}
+	})
+
+	t.Run("test ProcessLive()", func(t *testing.T) {
+		proc := testProcessor{whitelist: []string{"SessionTick"}}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		if err := ProcessLive(ctx, f, testHeaders, 0, &proc); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected ProcessLive() to return a deadline exceeded error. received %v", err)
+		}
+
+		if len(proc.results) != 3 {
+			t.Errorf("expected %d ticks to be processed. received %d", 3, len(proc.results))
+		}
+
+		if proc.session.WeekendInfo.TrackName != "monza full" {
+			t.Errorf("expected processed session track to be %s. received %s", "monza full", proc.session.WeekendInfo.TrackName)
+		}
+	})
+
+	t.Run("test ProcessLiveWithOptions() decoding", func(t *testing.T) {
+		proc := testProcessor{whitelist: []string{"SessionFlags", "PlayerTrackSurface"}}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+
+		if err := ProcessLiveWithOptions(ctx, f, testHeaders, LiveOptions{LegacyBitFields: true, Enums: true}, &proc); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected ProcessLiveWithOptions() to return a deadline exceeded error. received %v", err)
+		}
+
+		if len(proc.results) == 0 {
+			t.Error("expected ticks to be processed")
+			return
+		}
+
+		if _, ok := proc.results[0]["SessionFlags"].(string); !ok {
+			t.Errorf("expected SessionFlags to be a hex string. received %T", proc.results[0]["SessionFlags"])
+		}
+
+		if _, ok := proc.results[0]["PlayerTrackSurface"].(irsdk.TrkLoc); !ok {
+			t.Errorf("expected PlayerTrackSurface to be irsdk.TrkLoc. received %T", proc.results[0]["PlayerTrackSurface"])
+		}
+	})
+
+	t.Run("test ProcessLive() without tick rate", func(t *testing.T) {
+		telemetryHeader := *testHeaders.TelemetryHeader
+		telemetryHeader.TickRate = 0
+		header := *testHeaders
+		header.TelemetryHeader = &telemetryHeader
+
+		if err := ProcessLive(context.Background(), f, &header, 0, &testProcessor{}); err == nil {
+			t.Error("expected ProcessLive() to return an error without an interval or tick rate")
+		}
+	})
+
+	t.Run("test ProcessLive() err processor", func(t *testing.T) {
+		if err := ProcessLive(context.Background(), f, testHeaders, 0, &testErrorProcessor{}); err == nil {
+			t.Error("expected ProcessLive() to return an error")
+		}
+	})
+}
@@ -0,0 +1,389 @@
+package ibt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/utilities"
+)
+
+// DefaultGapThreshold is the number of seconds between the ticks of consecutive stubs of a MergedSession
+// after which the telemetry is considered to have a gap.
+const DefaultGapThreshold = 1.0
+
+// GapHandler can be implemented by processors that need to be notified of gaps in the telemetry of a merged session.
+//
+// For example, when the driver got out of the car and the telemetry of the session continues in a new file.
+// HandleGap is called before the first tick after the gap, with the SessionTime of the ticks on either side of it.
+type GapHandler interface {
+	HandleGap(from, to float64) error
+}
+
+// MergedTick is a single tick of a MergedSession.
+type MergedTick struct {
+	// Decoded whitelisted variables of the tick
+	Tick Tick
+	// Stub the tick was read from
+	Stub Stub
+	// Index of the record of the tick in the stub
+	Record int
+	// SessionTime of the tick
+	SessionTime float64
+	// Whether the tick is the first after a gap in the telemetry
+	Gap bool
+}
+
+// MergedSession iterates the ticks of all stubs of a session as a single stream ordered by SessionTime.
+//
+// Stubs are read in order of the SessionTime at which they start. Ticks of a stub that overlap with the ticks
+// already read from a previous stub are skipped, and the first tick of a stub that starts more than the gap
+// threshold after the previous tick is marked as a gap. Use StubGroup.Group to find the stubs of a session.
+type MergedSession struct {
+	stubs     StubGroup
+	whitelist []string
+	threshold float64
+
+	// Decoding modes applied to the parser of every stub
+	legacyBitFields bool
+	enums           bool
+
+	// Stub currently being read and it's parser
+	idx         int
+	parser      *Parser
+	closeReader func() error
+	sessionTime Accessor[float64]
+	record      int
+	// Whether a tick of the current stub has been read
+	stubStarted bool
+
+	// SessionTime of the last tick that was read
+	last    float64
+	started bool
+	err     error
+
+	// Decoded tick that will be returned by the next call to Next
+	pending    MergedTick
+	hasPending bool
+	prefetched bool
+}
+
+// NewMergedSession creates a MergedSession of the stubs that decodes the given whitelisted variables.
+//
+// If no whitelist or a single value of "*" is received, all variables will be decoded. The stubs are not modified.
+func NewMergedSession(stubs StubGroup, whitelist ...string) (*MergedSession, error) {
+	if len(stubs) == 0 {
+		return nil, errors.New("no stubs to merge")
+	}
+
+	for _, stub := range stubs {
+		if stub.header == nil || stub.header.DiskHeader == nil || stub.header.SessionInfo == nil {
+			return nil, fmt.Errorf("stub %s does not have the headers of an ibt file", stub.Filename())
+		}
+
+		if vh, ok := stub.header.VarHeader["SessionTime"]; !ok || vh.Rtype != 5 {
+			return nil, fmt.Errorf("variable SessionTime not found in stub %s", stub.Filename())
+		}
+	}
+
+	sorted := make(StubGroup, len(stubs))
+	copy(sorted, stubs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].header.DiskHeader.StartTime < sorted[j].header.DiskHeader.StartTime
+	})
+
+	return &MergedSession{stubs: sorted, whitelist: whitelist, threshold: DefaultGapThreshold}, nil
+}
+
+// Stubs of the merged session, ordered by the SessionTime at which they start.
+func (m *MergedSession) Stubs() StubGroup { return m.stubs }
+
+// Header of the latest stub of the session.
+func (m *MergedSession) Header() *headers.Header { return m.stubs[len(m.stubs)-1].header }
+
+// Session info of the latest stub of the session.
+//
+// The latest stub is used, since it contains the results that have accumulated during the whole session.
+func (m *MergedSession) Session() *headers.Session { return m.Header().SessionInfo }
+
+// SetGapThreshold sets the number of seconds between the ticks of consecutive stubs after which a gap is marked.
+//
+// Defaults to DefaultGapThreshold.
+func (m *MergedSession) SetGapThreshold(seconds float64) { m.threshold = seconds }
+
+// UseLegacyBitFields configures the merged session to read bitfield variables as hex strings.
+// See Parser.UseLegacyBitFields.
+func (m *MergedSession) UseLegacyBitFields(enabled bool) { m.legacyBitFields = enabled }
+
+// UseEnums configures the merged session to read known enum variables as typed irsdk enums. See Parser.UseEnums.
+func (m *MergedSession) UseEnums(enabled bool) { m.enums = enabled }
+
+// Next returns the next tick of the merged session and whether it can be called again.
+//
+// When all ticks have been read, an empty MergedTick with a nil Tick and false will be returned. Err should be
+// checked afterwards to determine whether all stubs were read successfully.
+func (m *MergedSession) Next() (MergedTick, bool) {
+	if !m.prefetched {
+		m.prefetched = true
+		m.pending, m.hasPending = m.read()
+	}
+
+	if !m.hasPending {
+		return MergedTick{}, false
+	}
+
+	tick := m.pending
+	m.pending, m.hasPending = m.read()
+
+	return tick, m.hasPending
+}
+
+// Err returns the first error that occurred while opening the stubs.
+func (m *MergedSession) Err() error { return m.err }
+
+// Close the stub that is currently being read.
+func (m *MergedSession) Close() error {
+	if m.closeReader == nil {
+		return nil
+	}
+
+	err := m.closeReader()
+	m.parser, m.closeReader = nil, nil
+
+	return err
+}
+
+// read returns the next tick with it's whitelisted variables decoded.
+func (m *MergedSession) read() (MergedTick, bool) {
+	raw, tick, ok := m.nextRaw()
+	if !ok {
+		return MergedTick{}, false
+	}
+
+	tick.Tick = m.parser.readVarsFromBuffer(raw.buf)
+
+	return tick, true
+}
+
+// nextRaw reads the next tick of the merged session without decoding any of it's variables.
+//
+// The returned RawTick is only valid until the next call to nextRaw.
+func (m *MergedSession) nextRaw() (RawTick, MergedTick, bool) {
+	for m.err == nil && m.idx < len(m.stubs) {
+		if m.parser == nil {
+			if err := m.openStub(); err != nil {
+				m.err = err
+				break
+			}
+		}
+
+		raw, _ := m.parser.NextRaw()
+		if raw.buf == nil {
+			if err := m.Close(); err != nil {
+				m.err = err
+			}
+			m.idx++
+			continue
+		}
+
+		record := m.record
+		m.record++
+
+		sessionTime := m.sessionTime.Value()
+
+		gap := false
+		if m.started && !m.stubStarted {
+			// Skip the ticks that overlap with the previous stub
+			if sessionTime <= m.last {
+				continue
+			}
+			gap = sessionTime-m.last > m.threshold
+		}
+
+		m.started, m.stubStarted = true, true
+		m.last = sessionTime
+
+		return raw, MergedTick{Stub: m.stubs[m.idx], Record: record, SessionTime: sessionTime, Gap: gap}, true
+	}
+
+	return RawTick{}, MergedTick{}, false
+}
+
+// openStub opens the current stub and creates it's parser.
+func (m *MergedSession) openStub() error {
+	stub := m.stubs[m.idx]
+
+	reader, closeReader, err := stub.reader()
+	if err != nil {
+		return err
+	}
+
+	parser := NewParser(reader, stub.header, validWhitelist(stub.header.VarHeader, m.whitelist)...)
+	parser.UseLegacyBitFields(m.legacyBitFields)
+	parser.UseEnums(m.enums)
+
+	sessionTime, err := parser.Float64("SessionTime")
+	if err != nil {
+		closeReader()
+		return fmt.Errorf("failed to merge stub %s: %v", stub.Filename(), err)
+	}
+
+	m.parser, m.closeReader, m.sessionTime = parser, closeReader, sessionTime
+	m.record = parser.current
+	m.stubStarted = false
+
+	return nil
+}
+
+// ProcessMerged processes the telemetry of the stubs of a session as a single stream of ticks using a MergedSession.
+//
+// Unlike Process, hasNext is only false for the last tick of the session, and the session info of the latest stub
+// is passed to the processors for every tick. Processors that implement GapHandler are notified of gaps between
+// stubs and processors that implement GroupFinisher are notified once all ticks were processed.
+//
+// The first error returned by a processor stops processing and is returned as a *ProcessError, while
+// cancellation of the context is handled in the same manner as Process.
+func ProcessMerged(ctx context.Context, stubs StubGroup, processors ...Processor) error {
+	return ProcessMergedWithOptions(ctx, stubs, ProcessOptions{}, processors...)
+}
+
+// ProcessMergedWithOptions processes the merged session of the stubs in the same manner as ProcessMerged,
+// decoding the ticks according to the LegacyBitFields and Enums of the options. The remaining options
+// only apply to Process and ProcessParallel.
+func ProcessMergedWithOptions(ctx context.Context, stubs StubGroup, opts ProcessOptions, processors ...Processor) error {
+	whitelist := make([]string, 0)
+	for _, stub := range stubs {
+		if stub.header != nil {
+			whitelist = append(whitelist, buildWhitelist(stub.header.VarHeader, processors...)...)
+		}
+	}
+
+	merged, err := NewMergedSession(stubs, utilities.GetDistinct(whitelist)...)
+	if err != nil {
+		return err
+	}
+	defer merged.Close()
+
+	merged.UseLegacyBitFields(opts.LegacyBitFields)
+	merged.UseEnums(opts.Enums)
+
+	session := merged.Session()
+
+	var last float64
+	for tick := 0; ; tick++ {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("processing of merged session stopped at tick %d: %w", tick, ctx.Err())
+		default:
+		}
+
+		input, hasNext := merged.Next()
+		if input.Tick == nil {
+			break
+		}
+
+		if input.Gap {
+			if err := handleGap(last, input.SessionTime, processors...); err != nil {
+				return err
+			}
+		}
+		last = input.SessionTime
+
+		for _, proc := range processors {
+			if err := proc.Process(input.Tick.Filter(proc.Whitelist()...), hasNext, session); err != nil {
+				return &ProcessError{Filename: input.Stub.Filename(), Tick: input.Record, Processor: proc, Err: err}
+			}
+		}
+
+		if !hasNext {
+			break
+		}
+	}
+
+	if err := merged.Err(); err != nil {
+		return err
+	}
+
+	return finishGroup(merged.Stubs(), processors...)
+}
+
+// handleGap notifies all GapHandler processors of a gap in the telemetry
+func handleGap(from, to float64, processors ...Processor) error {
+	for _, proc := range processors {
+		if handler, ok := proc.(GapHandler); ok {
+			if err := handler.HandleGap(from, to); err != nil {
+				return fmt.Errorf("failed to handle gap from %f to %f: %w", from, to, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// WriteMerged writes the ticks of a MergedSession of the stubs to w as a single ibt file.
+//
+// All stubs must have the same variables. The session info of the latest stub is written, while the
+// DiskHeader is recalculated from the merged ticks.
+func WriteMerged(w io.WriteSeeker, stubs StubGroup) error {
+	merged, err := NewMergedSession(stubs)
+	if err != nil {
+		return err
+	}
+	defer merged.Close()
+
+	first := merged.Stubs()[0]
+	header := *merged.Header()
+
+	for _, stub := range merged.Stubs() {
+		if stub.header.TelemetryHeader.BufLen != header.TelemetryHeader.BufLen || !reflect.DeepEqual(stub.header.VarHeader, header.VarHeader) {
+			return fmt.Errorf("stub %s does not have the same variables as the other stubs", stub.Filename())
+		}
+	}
+
+	// The DiskHeader of the earliest stub is used as starting point, as it contains the StartDate of the session
+	header.DiskHeader = first.header.DiskHeader
+
+	writer, err := NewWriter(w, &header)
+	if err != nil {
+		return fmt.Errorf("failed to create writer for merged session: %v", err)
+	}
+
+	disk := writer.Header().DiskHeader
+	laps := make(map[interface{}]struct{})
+
+	for {
+		raw, tick, ok := merged.nextRaw()
+		if !ok {
+			break
+		}
+
+		if lap, ok := raw.Get("Lap"); ok {
+			laps[lap] = struct{}{}
+		}
+		if writer.Records() == 0 {
+			disk.StartTime = tick.SessionTime
+		}
+		disk.EndTime = tick.SessionTime
+
+		if err := writer.WriteRaw(raw); err != nil {
+			return fmt.Errorf("failed to write merged session: %v", err)
+		}
+	}
+
+	if err := merged.Err(); err != nil {
+		return err
+	}
+
+	if writer.Records() == 0 {
+		return errors.New("no ticks found to merge")
+	}
+
+	disk.LapCount = len(laps)
+	disk.StartDate = first.header.DiskHeader.StartDate + int64(disk.StartTime-first.header.DiskHeader.StartTime)
+
+	return writer.Close()
+}
@@ -0,0 +1,282 @@
+package ibt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
+)
+
+// testGapProcessor records the ticks, gaps and end of the group it receives
+type testGapProcessor struct {
+	testLifecycleProcessor
+	hasNext []bool
+}
+
+func (t *testGapProcessor) Process(input Tick, hasNext bool, session *headers.Session) error {
+	t.hasNext = append(t.hasNext, hasNext)
+	return t.testLifecycleProcessor.Process(input, hasNext, session)
+}
+
+func (t *testGapProcessor) HandleGap(from, to float64) error {
+	t.events = append(t.events, fmt.Sprintf("gap %d", len(t.results)))
+	return t.err
+}
+
+// writeSessionTestFiles trims the testing file into overlapping stubs and a stub after a gap.
+//
+// The merged ticks are records 0 to 249 and 350 to 389 of the testing file.
+func writeSessionTestFiles(t *testing.T) StubGroup {
+	stubs, err := ParseLazyStubs(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to parse stubs for testing file - %v", err)
+	}
+
+	dir := t.TempDir()
+	files := make([]string, 0)
+	// Written out of order to ensure the stubs are sorted by SessionTime
+	for idx, r := range [][2]int{{350, 0}, {100, 250}, {0, 150}} {
+		path := filepath.Join(dir, fmt.Sprintf("part%d.ibt", idx))
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("failed to create file %s - %v", path, err)
+		}
+
+		if err := Trim(f, stubs[0], r[0], r[1]); err != nil {
+			t.Fatalf("failed to trim testing file - %v", err)
+		}
+		f.Close()
+
+		files = append(files, path)
+	}
+
+	parts, err := ParseLazyStubs(files...)
+	if err != nil {
+		t.Fatalf("failed to parse stubs for trimmed files - %v", err)
+	}
+
+	return parts
+}
+
+func TestMergedSession(t *testing.T) {
+	stubs := writeSessionTestFiles(t)
+
+	original, _ := ParseLazyStubs(".testing/valid_test_file.ibt")
+	frame, err := LoadChannels(original[0], "SessionTime", "Speed")
+	if err != nil {
+		t.Fatalf("failed to load channels for testing file - %v", err)
+	}
+	sessionTime, _ := GetColumn[[]float64](frame, "SessionTime")
+	speed, _ := GetColumn[[]float32](frame, "Speed")
+
+	expectedTimes := append(append([]float64{}, sessionTime[:250]...), sessionTime[350:]...)
+	expectedSpeed := append(append([]float32{}, speed[:250]...), speed[350:]...)
+
+	t.Run("test MergedSession Next", func(t *testing.T) {
+		merged, err := NewMergedSession(stubs, "Speed")
+		if err != nil {
+			t.Errorf("expected NewMergedSession() to run without err. received error: %v", err)
+			return
+		}
+		defer merged.Close()
+
+		times := make([]float64, 0)
+		gaps := make([]int, 0)
+		// Record index of the first tick read from each stub
+		firstRecords := make([]int, 0)
+		filename := ""
+		for {
+			tick, hasNext := merged.Next()
+			if tick.Tick == nil {
+				break
+			}
+
+			if tick.Stub.Filename() != filename {
+				filename = tick.Stub.Filename()
+				firstRecords = append(firstRecords, tick.Record)
+			}
+
+			if tick.Tick["Speed"] != expectedSpeed[len(times)] {
+				t.Errorf("expected speed %v at tick %d. received %v", expectedSpeed[len(times)], len(times), tick.Tick["Speed"])
+				return
+			}
+			if tick.Gap {
+				gaps = append(gaps, len(times))
+			}
+			times = append(times, tick.SessionTime)
+
+			if !hasNext {
+				break
+			}
+		}
+
+		if err := merged.Err(); err != nil {
+			t.Errorf("expected Err() to be nil. received error: %v", err)
+		}
+
+		if !reflect.DeepEqual(times, expectedTimes) {
+			t.Errorf("expected %d ticks ordered by session time. received %d", len(expectedTimes), len(times))
+		}
+
+		if !reflect.DeepEqual(gaps, []int{250}) {
+			t.Errorf("expected a single gap at tick %d. received %v", 250, gaps)
+		}
+
+		// The overlapping records of the second stub are skipped, while the first record of the last stub is kept
+		if !reflect.DeepEqual(firstRecords, []int{0, 50, 0}) {
+			t.Errorf("expected the stubs to start at records %v. received %v", []int{0, 50, 0}, firstRecords)
+		}
+
+		if tick, hasNext := merged.Next(); tick.Tick != nil || hasNext {
+			t.Error("expected an empty tick after the end of the session")
+		}
+	})
+
+	t.Run("test MergedSession gap threshold", func(t *testing.T) {
+		merged, _ := NewMergedSession(stubs, "Lap")
+		defer merged.Close()
+		merged.SetGapThreshold(5)
+
+		for {
+			tick, hasNext := merged.Next()
+			if tick.Gap {
+				t.Errorf("expected no gaps with a threshold of 5 seconds. received gap at %f", tick.SessionTime)
+			}
+			if !hasNext {
+				break
+			}
+		}
+	})
+
+	t.Run("test MergedSession session", func(t *testing.T) {
+		merged, _ := NewMergedSession(stubs)
+
+		if merged.Stubs()[0].Filename() != stubs[2].Filename() || merged.Header() != stubs[0].Headers() {
+			t.Error("expected stubs to be ordered by session time with the latest stub last")
+		}
+
+		if merged.Session() != stubs[0].Headers().SessionInfo {
+			t.Error("expected the session info of the latest stub")
+		}
+	})
+
+	t.Run("test NewMergedSession invalid stubs", func(t *testing.T) {
+		if _, err := NewMergedSession(nil); err == nil {
+			t.Error("expected NewMergedSession() to return an error for no stubs")
+		}
+
+		if _, err := NewMergedSession(StubGroup{{filepath: "live"}}); err == nil {
+			t.Error("expected NewMergedSession() to return an error for a stub without headers")
+		}
+	})
+
+	t.Run("test WriteMerged", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "merged.ibt")
+		f, err := os.Create(path)
+		if err != nil {
+			t.Fatalf("failed to create file %s - %v", path, err)
+		}
+		defer f.Close()
+
+		if err := WriteMerged(f, stubs); err != nil {
+			t.Errorf("expected WriteMerged() to run without err. received error: %v", err)
+			return
+		}
+
+		written, err := ParseLazyStubs(path)
+		if err != nil {
+			t.Errorf("expected merged file to be parsed without err. received error: %v", err)
+			return
+		}
+
+		disk := written[0].Headers().DiskHeader
+		if disk.RecordCount != len(expectedTimes) || disk.StartTime != sessionTime[0] || disk.EndTime != sessionTime[389] || disk.LapCount != 1 {
+			t.Errorf("expected %d records from %f to %f. received %+v", len(expectedTimes), sessionTime[0], sessionTime[389], disk)
+		}
+
+		merged, err := LoadChannels(written[0], "SessionTime")
+		if err != nil {
+			t.Errorf("expected merged channels to load without err. received error: %v", err)
+			return
+		}
+
+		times, _ := GetColumn[[]float64](merged, "SessionTime")
+		if !reflect.DeepEqual(times, expectedTimes) {
+			t.Error("expected merged records to be written in order")
+		}
+	})
+}
+
+func TestProcessMerged(t *testing.T) {
+	stubs := writeSessionTestFiles(t)
+
+	t.Run("test ProcessMerged", func(t *testing.T) {
+		proc := &testGapProcessor{testLifecycleProcessor: testLifecycleProcessor{testProcessor: testProcessor{whitelist: []string{"Lap"}}}}
+		if err := ProcessMerged(context.Background(), stubs, proc); err != nil {
+			t.Errorf("expected ProcessMerged() to run without err. received error: %v", err)
+			return
+		}
+
+		if len(proc.results) != 290 {
+			t.Errorf("expected %d ticks to be processed. received %d", 290, len(proc.results))
+		}
+
+		for idx, hasNext := range proc.hasNext {
+			if hasNext != (idx < 289) {
+				t.Errorf("expected hasNext to only be false for the last tick. received %v at tick %d", hasNext, idx)
+				break
+			}
+		}
+
+		expected := []string{"gap 250", "group 3"}
+		if !reflect.DeepEqual(proc.events, expected) {
+			t.Errorf("expected events %v. received %v", expected, proc.events)
+		}
+
+		if proc.session != stubs[0].Headers().SessionInfo {
+			t.Error("expected the session info of the latest stub")
+		}
+	})
+
+	t.Run("test ProcessMergedWithOptions decoding", func(t *testing.T) {
+		proc := &testProcessor{whitelist: []string{"PlayerTrackSurface", "SessionFlags"}}
+		opts := ProcessOptions{LegacyBitFields: true, Enums: true}
+		if err := ProcessMergedWithOptions(context.Background(), stubs, opts, proc); err != nil {
+			t.Errorf("expected ProcessMergedWithOptions() to run without err. received error: %v", err)
+			return
+		}
+
+		last := proc.results[len(proc.results)-1]
+		if _, ok := last["PlayerTrackSurface"].(irsdk.TrkLoc); !ok {
+			t.Errorf("expected PlayerTrackSurface to be irsdk.TrkLoc. received %T", last["PlayerTrackSurface"])
+		}
+
+		if _, ok := last["SessionFlags"].(string); !ok {
+			t.Errorf("expected SessionFlags to be a hex string. received %T", last["SessionFlags"])
+		}
+	})
+
+	t.Run("test ProcessMerged errors", func(t *testing.T) {
+		proc := &testGapProcessor{testLifecycleProcessor: testLifecycleProcessor{err: errors.New("gap error")}}
+		if err := ProcessMerged(context.Background(), stubs, proc); err == nil || !errors.Is(err, proc.err) {
+			t.Errorf("expected the gap error to be returned. received %v", err)
+		}
+
+		var processErr *ProcessError
+		if err := ProcessMerged(context.Background(), stubs, &testFailingProcessor{every: 100}); !errors.As(err, &processErr) {
+			t.Errorf("expected a *ProcessError. received %v", err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		if err := ProcessMerged(ctx, stubs, &testProcessor{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("expected context.Canceled. received %v", err)
+		}
+	})
+}
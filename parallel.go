@@ -0,0 +1,175 @@
+package ibt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+// ProcessorFactory creates the processors for a single group of stubs.
+//
+// A factory allows processors that keep per-session state to have one instance per group when processing
+// in parallel. Processors that should receive the ticks of all groups can be shared with SharedProcessors.
+type ProcessorFactory func(group StubGroup) ([]Processor, error)
+
+// ConcurrentProcessor can be implemented by processors that are safe for concurrent use.
+//
+// Shared processors that do not implement this interface, or return false, will have their calls
+// serialised when processing in parallel.
+type ConcurrentProcessor interface {
+	Processor
+	ConcurrencySafe() bool
+}
+
+// SharedProcessors creates a ProcessorFactory that returns the same processor instances for every group.
+//
+// Processors that are not safe for concurrent use (see ConcurrentProcessor) are wrapped to ensure that only
+// a single tick is processed at a time. The ticks of each group are still processed in order, but the ticks
+// of different groups will be interleaved. Lifecycle hooks, such as GroupFinisher, are called for every group.
+func SharedProcessors(processors ...Processor) ProcessorFactory {
+	shared := make([]Processor, 0, len(processors))
+
+	for _, proc := range processors {
+		if concurrent, ok := proc.(ConcurrentProcessor); ok && concurrent.ConcurrencySafe() {
+			shared = append(shared, proc)
+			continue
+		}
+
+		shared = append(shared, &synchronizedProcessor{proc: proc})
+	}
+
+	return func(group StubGroup) ([]Processor, error) { return shared, nil }
+}
+
+// ProcessParallel processes the groups concurrently with a bounded number of workers.
+//
+// Each group is processed in the same manner as Process, meaning the stubs of a group are processed in order
+// and the ticks of each stub are processed in order. The factory is called once for every group to create
+// it's processors. Independent stubs can be processed concurrently by placing each stub in it's own group.
+//
+// All groups are processed, even when some fail. The errors of all failed groups are joined with errors.Join.
+// When a Progress callback is configured, it reports the progress of each group separately and may be called
+// concurrently from different groups.
+func ProcessParallel(ctx context.Context, groups []StubGroup, opts ProcessOptions, factory ProcessorFactory) error {
+	workers := opts.Workers
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(groups) {
+		workers = len(groups)
+	}
+
+	jobs := make(chan int)
+	errs := make([]error, len(groups))
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for idx := range jobs {
+				errs[idx] = processGroup(ctx, groups[idx], opts, factory)
+			}
+		}()
+	}
+
+	for idx := range groups {
+		jobs <- idx
+	}
+	close(jobs)
+
+	wg.Wait()
+
+	return errors.Join(errs...)
+}
+
+// processGroup creates the processors of a single group and processes it.
+func processGroup(ctx context.Context, group StubGroup, opts ProcessOptions, factory ProcessorFactory) error {
+	if len(group) == 0 {
+		return nil
+	}
+
+	processors, err := factory(group)
+	if err != nil {
+		return fmt.Errorf("failed to create processors for group of %s: %w", group[0].Filename(), err)
+	}
+
+	if err := ProcessWithOptions(ctx, group, opts, processors...); err != nil {
+		return fmt.Errorf("failed to process group of %s: %w", group[0].Filename(), err)
+	}
+
+	return nil
+}
+
+// synchronizedProcessor serialises calls to a processor that is not safe for concurrent use.
+type synchronizedProcessor struct {
+	mu   sync.Mutex
+	proc Processor
+}
+
+// unwrapProcessor returns the processor wrapped by SharedProcessors, or the processor itself when it is not wrapped.
+func unwrapProcessor(proc Processor) Processor {
+	if s, ok := proc.(*synchronizedProcessor); ok {
+		return s.proc
+	}
+
+	return proc
+}
+
+func (s *synchronizedProcessor) Process(input Tick, hasNext bool, session *headers.Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.proc.Process(input, hasNext, session)
+}
+
+func (s *synchronizedProcessor) Whitelist() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.proc.Whitelist()
+}
+
+// StartStub forwards the start of a stub to the wrapped processor, if it is a StubStarter
+func (s *synchronizedProcessor) StartStub(stub Stub) error {
+	starter, ok := s.proc.(StubStarter)
+	if !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return starter.StartStub(stub)
+}
+
+// FinishStub forwards the end of a stub to the wrapped processor, if it is a StubFinisher
+func (s *synchronizedProcessor) FinishStub(stub Stub) error {
+	finisher, ok := s.proc.(StubFinisher)
+	if !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return finisher.FinishStub(stub)
+}
+
+// FinishGroup forwards the end of a group to the wrapped processor, if it is a GroupFinisher
+func (s *synchronizedProcessor) FinishGroup(group StubGroup) error {
+	finisher, ok := s.proc.(GroupFinisher)
+	if !ok {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return finisher.FinishGroup(group)
+}
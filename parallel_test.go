@@ -0,0 +1,171 @@
+package ibt
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+type testCountProcessor struct {
+	count int
+}
+
+func (t *testCountProcessor) Process(input Tick, hasNext bool, session *headers.Session) error {
+	t.count++
+	return nil
+}
+
+func (t *testCountProcessor) Whitelist() []string { return []string{"Lap"} }
+
+type testConcurrentProcessor struct {
+	testCountProcessor
+}
+
+func (t *testConcurrentProcessor) ConcurrencySafe() bool { return true }
+
+func parseTestGroups(t *testing.T, n int) []StubGroup {
+	groups := make([]StubGroup, 0, n)
+
+	for i := 0; i < n; i++ {
+		stubs, err := ParseStubs(".testing/valid_test_file.ibt")
+		if err != nil {
+			t.Fatalf("failed to parse stubs for testing file - %v", err)
+		}
+
+		groups = append(groups, stubs)
+	}
+
+	return groups
+}
+
+func TestProcessParallel(t *testing.T) {
+	groups := parseTestGroups(t, 4)
+	defer CloseAllStubs(groups)
+
+	t.Run("test ProcessParallel processor per group", func(t *testing.T) {
+		var mu sync.Mutex
+		processors := make([]*testProcessor, 0)
+
+		factory := func(group StubGroup) ([]Processor, error) {
+			proc := &testProcessor{whitelist: []string{"LapCurrentLapTime"}}
+
+			mu.Lock()
+			processors = append(processors, proc)
+			mu.Unlock()
+
+			return []Processor{proc}, nil
+		}
+
+		if err := ProcessParallel(context.Background(), groups, ProcessOptions{Workers: 2}, factory); err != nil {
+			t.Errorf("expected ProcessParallel() to run without err. received error: %v", err)
+		}
+
+		if len(processors) != 4 {
+			t.Errorf("expected a processor to be created for each of the %d groups. received %d", 4, len(processors))
+			return
+		}
+
+		for _, proc := range processors {
+			if len(proc.results) != 390 || proc.results[0]["LapCurrentLapTime"].(float32) != 37.6619 {
+				t.Errorf("expected every group to be processed in order. received %d ticks", len(proc.results))
+			}
+		}
+	})
+
+	t.Run("test ProcessParallel shared processors", func(t *testing.T) {
+		unsafe := &testCountProcessor{}
+		safe := &testConcurrentProcessor{}
+
+		factory := SharedProcessors(unsafe, safe)
+
+		processors, _ := factory(nil)
+		if _, ok := processors[0].(*synchronizedProcessor); !ok {
+			t.Errorf("expected processor that is not concurrency safe to be synchronized. received %T", processors[0])
+		}
+		if processors[1] != safe {
+			t.Errorf("expected concurrency safe processor to be shared directly. received %T", processors[1])
+		}
+
+		if err := ProcessParallel(context.Background(), groups, ProcessOptions{Workers: 1}, factory); err != nil {
+			t.Errorf("expected ProcessParallel() to run without err. received error: %v", err)
+		}
+
+		if unsafe.count != 4*390 {
+			t.Errorf("expected shared processor to process %d ticks. received %d", 4*390, unsafe.count)
+		}
+	})
+
+	t.Run("test ProcessParallel shared lifecycle hooks", func(t *testing.T) {
+		proc := &testLifecycleProcessor{testProcessor: testProcessor{whitelist: []string{"Lap"}}}
+
+		if err := ProcessParallel(context.Background(), groups, ProcessOptions{Workers: 2}, SharedProcessors(proc)); err != nil {
+			t.Errorf("expected ProcessParallel() to run without err. received error: %v", err)
+		}
+
+		if len(proc.events) != 3*4 {
+			t.Errorf("expected lifecycle hooks to be forwarded for every group. received %v", proc.events)
+		}
+	})
+
+	t.Run("test ProcessParallel errors", func(t *testing.T) {
+		calls := 0
+		var mu sync.Mutex
+
+		factoryErr := errors.New("factory error")
+		factory := func(group StubGroup) ([]Processor, error) {
+			mu.Lock()
+			defer mu.Unlock()
+
+			calls++
+			if calls == 1 {
+				return nil, factoryErr
+			}
+
+			return []Processor{&testErrorProcessor{}}, nil
+		}
+
+		err := ProcessParallel(context.Background(), groups, ProcessOptions{}, factory)
+		if err == nil {
+			t.Error("expected ProcessParallel() to return an error")
+			return
+		}
+
+		if calls != 4 {
+			t.Errorf("expected all %d groups to be processed after an error. received %d", 4, calls)
+		}
+
+		if count := strings.Count(err.Error(), "unit test error"); count != 3 || !strings.Contains(err.Error(), "factory error") {
+			t.Errorf("expected errors of all groups to be joined. received %v", err)
+		}
+
+		if !errors.Is(err, factoryErr) {
+			t.Errorf("expected the factory error to be wrapped. received %v", err)
+		}
+	})
+
+	t.Run("test ProcessParallel shared processor errors", func(t *testing.T) {
+		proc := &testFailingProcessor{every: 100}
+
+		err := ProcessParallel(context.Background(), groups, ProcessOptions{ErrorPolicy: ErrorPolicyCollect}, SharedProcessors(proc))
+
+		var errs ProcessErrors
+		if !errors.As(err, &errs) {
+			t.Errorf("expected ProcessErrors to be returned. received %v", err)
+			return
+		}
+
+		if len(errs.Processor(proc)) != len(errs) || len(errs) == 0 {
+			t.Errorf("expected all errors to be returned by the shared processor. received %d of %d", len(errs.Processor(proc)), len(errs))
+		}
+	})
+
+	t.Run("test ProcessParallel no groups", func(t *testing.T) {
+		if err := ProcessParallel(context.Background(), nil, ProcessOptions{}, SharedProcessors()); err != nil {
+			t.Errorf("expected ProcessParallel() to run without err. received error: %v", err)
+		}
+	})
+}
@@ -1,6 +1,8 @@
 package ibt
 
 import (
+	"io"
+
 	"github.com/teamjorge/ibt/headers"
 )
 
@@ -13,6 +15,17 @@ type Parser struct {
 	header    *headers.Header
 
 	current int
+	// Exclusive record limit set by SeekRange. A value of 0 means the parser reads until the end of the file.
+	end int
+
+	// Read bitfields as hex strings instead of typed irsdk bitfields
+	legacyBitFields bool
+	// Read known enum variables as typed irsdk enums instead of ints
+	enums bool
+
+	// Reusable buffers for raw tick parsing
+	buf  []byte
+	peek []byte
 }
 
 // NewParser creates a new parser from a given ibt file, it's headers, and a variable whitelist.
@@ -30,8 +43,6 @@ func NewParser(reader headers.Reader, header *headers.Header, whitelist ...strin
 	p.whitelist = whitelist
 	p.header = header
 
-	p.current = 1
-
 	return p
 }
 
@@ -42,22 +53,48 @@ func NewParser(reader headers.Reader, header *headers.Header, whitelist ...strin
 //
 // Should expected variable values be missing, please ensure that they are added to the Parser whitelist.
 func (p *Parser) Next() (Tick, bool) {
-	start := p.header.TelemetryHeader.BufOffset + (p.current * p.header.TelemetryHeader.BufLen)
-
-	currentBuf := p.read(start)
-	if currentBuf == nil {
+	raw, hasNext := p.NextRaw()
+	if raw.buf == nil {
 		return nil, false
 	}
 
-	// Read in the next buffer to determine if more telemetry ticks are available.
-	nextStart := p.header.TelemetryHeader.BufOffset + ((p.current + 1) * p.header.TelemetryHeader.BufLen)
-	nextBuf := p.read(nextStart)
+	newVars := p.readVarsFromBuffer(raw.buf)
 
-	newVars := p.readVarsFromBuffer(currentBuf)
+	return newVars, hasNext
+}
+
+// NextRaw reads the next tick of telemetry without decoding any of its variables and returns whether it can be called again.
+//
+// The returned RawTick shares a buffer owned by the Parser, meaning it is only valid until the next call to NextRaw or Next.
+// This allows a whole file to be iterated without any per-tick allocations when used with accessors such as Parser.Float32().
+//
+// When the buffer has reached the end, an empty RawTick and false will be returned.
+func (p *Parser) NextRaw() (RawTick, bool) {
+	if len(p.buf) != p.header.TelemetryHeader.BufLen {
+		p.buf = make([]byte, p.header.TelemetryHeader.BufLen)
+		p.peek = make([]byte, 1)
+	}
+
+	if p.end > 0 && p.current >= p.end {
+		return RawTick{}, false
+	}
+
+	start := p.header.TelemetryHeader.BufOffset + (p.current * p.header.TelemetryHeader.BufLen)
+	if _, err := p.reader.ReadAt(p.buf, int64(start)); err != nil {
+		return RawTick{}, false
+	}
+
+	// Read the last byte of the next buffer to determine if more telemetry ticks are available.
+	nextEnd := start + (2 * p.header.TelemetryHeader.BufLen) - 1
+	_, err := p.reader.ReadAt(p.peek, int64(nextEnd))
 
 	p.current++
 
-	return newVars, nextBuf != nil
+	if p.end > 0 && p.current >= p.end {
+		err = io.EOF
+	}
+
+	return RawTick{buf: p.buf, vars: p.header.VarHeader, legacyBitFields: p.legacyBitFields, enums: p.enums}, err == nil
 }
 
 // ParseAt the given buffer offset and return a processed tick.
@@ -90,19 +127,44 @@ func (p *Parser) read(start int) []byte {
 
 // readVarsFromBuffer reads each of the specified (whitelist) fields from the given buffer into a new Tick.
 func (p *Parser) readVarsFromBuffer(buf []byte) Tick {
-	newVars := make(Tick)
+	newVars := make(Tick, len(p.whitelist))
 
 	for _, variable := range p.whitelist {
-		item := p.header.VarHeader[variable]
-		val := readVarValue(buf, item)
-		newVars[variable] = val
+		newVars[variable] = readVar(buf, p.header.VarHeader[variable], p.legacyBitFields, p.enums)
 	}
 
 	return newVars
 }
 
 // Seek the parser to a specific tick within the ibt file.
-func (p *Parser) Seek(iter int) { p.current = iter }
+//
+// Any range limit set by SeekRange or SeekLap is removed.
+func (p *Parser) Seek(iter int) {
+	p.current = iter
+	p.end = 0
+}
+
+// SeekRange seeks the parser to the start tick and limits it to the ticks before end.
+//
+// Once the end has been reached, Next will return false, the same as it would at the end of the file.
+func (p *Parser) SeekRange(start, end int) {
+	p.current = start
+	p.end = end
+}
+
+// SeekLap limits the parser to the tick range of the given lap.
+func (p *Parser) SeekLap(lap Lap) { p.SeekRange(lap.Start, lap.End) }
+
+// UseLegacyBitFields configures the parser to read bitfield variables as hex strings, such as "0x10040200".
+//
+// By default, bitfields are read as typed irsdk bitfields, such as irsdk.SessionFlags.
+func (p *Parser) UseLegacyBitFields(enabled bool) { p.legacyBitFields = enabled }
+
+// UseEnums configures the parser to read known enum variables as typed irsdk enums.
+//
+// For example, PlayerTrackSurface will be read as an irsdk.TrkLoc and CarIdxTrackSurface as a []irsdk.TrkLoc.
+// By default, these variables are read as ints.
+func (p *Parser) UseEnums(enabled bool) { p.enums = enabled }
 
 // UpdateWhitelist replaces the current whitelist with the given fields
 func (p *Parser) UpdateWhitelist(whitelist ...string) {
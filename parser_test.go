@@ -10,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
 )
 
 func TestParser(t *testing.T) {
@@ -65,9 +66,9 @@ func TestParserNext(t *testing.T) {
 		p := NewParser(f, testHeaders, "LapCurrentLapTime")
 
 		expectedValues := []float32{
+			37.6619,
 			37.678566,
 			37.695232,
-			37.7119,
 		}
 
 		for idx, expectedValue := range expectedValues {
@@ -119,6 +120,47 @@ func TestParserNext(t *testing.T) {
 	})
 }
 
+func TestParserNextRaw(t *testing.T) {
+	f, err := os.Open(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to open testing file - %v", err)
+		return
+	}
+	defer f.Close()
+
+	testHeaders, err := headers.ParseHeaders(f)
+	if err != nil {
+		t.Errorf("failed to parse header for testing file - %v", err)
+		return
+	}
+
+	t.Run("test parser NextRaw() reach end of buffer", func(t *testing.T) {
+		p := NewParser(f, testHeaders)
+		p.current = 388
+
+		raw, next := p.NextRaw()
+		if value, _ := raw.Get("LapCurrentLapTime"); value != float32(44.128567) {
+			t.Errorf("expected LapCurrentLapTime value to equal %f, got %v", 44.128567, value)
+		}
+		if !next {
+			t.Error("expected additional raw ticks to be available after iteration")
+		}
+
+		raw, next = p.NextRaw()
+		if raw.Bytes() == nil {
+			t.Error("expected raw tick to have a buffer")
+		}
+		if next {
+			t.Error("expected no more raw ticks to be available after iteration")
+		}
+
+		raw, next = p.NextRaw()
+		if raw.Bytes() != nil || next {
+			t.Errorf("expected an empty raw tick and next to be false. received %v and %v", raw.Bytes(), next)
+		}
+	})
+}
+
 type testReader struct {
 	*bytes.Reader
 }
@@ -201,6 +243,126 @@ func TestSeek(t *testing.T) {
 	})
 }
 
+func TestSeekRange(t *testing.T) {
+	f, err := os.Open(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to open testing file - %v", err)
+		return
+	}
+	defer f.Close()
+
+	testHeaders, err := headers.ParseHeaders(f)
+	if err != nil {
+		t.Errorf("failed to parse header for testing file - %v", err)
+		return
+	}
+
+	t.Run("test seek range", func(t *testing.T) {
+		parser := NewParser(f, testHeaders, "Lap")
+
+		parser.SeekRange(10, 15)
+
+		count := 0
+		for {
+			tick, hasNext := parser.Next()
+			if tick == nil {
+				break
+			}
+			count++
+
+			if !hasNext {
+				break
+			}
+		}
+
+		if count != 5 {
+			t.Errorf("expected %d ticks to be parsed in range. received %d", 5, count)
+		}
+
+		if tick, hasNext := parser.Next(); tick != nil || hasNext {
+			t.Errorf("expected no ticks to be parsed after the range. received %v and %v", tick, hasNext)
+		}
+	})
+
+	t.Run("test seek removes range", func(t *testing.T) {
+		parser := NewParser(f, testHeaders, "Lap")
+
+		parser.SeekLap(Lap{Start: 10, End: 15})
+		parser.Seek(20)
+
+		if parser.current != 20 || parser.end != 0 {
+			t.Errorf("expected parser to be at %d without a range. received %d and %d", 20, parser.current, parser.end)
+		}
+	})
+}
+
+func TestUseLegacyBitFields(t *testing.T) {
+	f, err := os.Open(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to open testing file - %v", err)
+		return
+	}
+	defer f.Close()
+
+	testHeaders, err := headers.ParseHeaders(f)
+	if err != nil {
+		t.Errorf("failed to parse header for testing file - %v", err)
+		return
+	}
+
+	parser := NewParser(f, testHeaders, "SessionFlags")
+
+	tick, _ := parser.Next()
+	if flags, ok := tick["SessionFlags"].(irsdk.SessionFlags); !ok || !flags.Has(irsdk.StartHidden) {
+		t.Errorf("expected SessionFlags to be irsdk.SessionFlags with StartHidden set. received %v of type %T", tick["SessionFlags"], tick["SessionFlags"])
+	}
+
+	parser.UseLegacyBitFields(true)
+
+	tick, _ = parser.Next()
+	if tick["SessionFlags"] != "0x10040200" {
+		t.Errorf("expected SessionFlags to be %s. received %v", "0x10040200", tick["SessionFlags"])
+	}
+
+	raw, _ := parser.NextRaw()
+	if value, _ := raw.Get("SessionFlags"); value != "0x10040200" {
+		t.Errorf("expected raw SessionFlags to be %s. received %v", "0x10040200", value)
+	}
+}
+
+func TestUseEnums(t *testing.T) {
+	f, err := os.Open(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Errorf("failed to open testing file - %v", err)
+		return
+	}
+	defer f.Close()
+
+	testHeaders, err := headers.ParseHeaders(f)
+	if err != nil {
+		t.Errorf("failed to parse header for testing file - %v", err)
+		return
+	}
+
+	parser := NewParser(f, testHeaders, "PlayerTrackSurface", "Gear")
+
+	tick, _ := parser.Next()
+	if _, ok := tick["PlayerTrackSurface"].(int); !ok {
+		t.Errorf("expected PlayerTrackSurface to be an int by default. received %T", tick["PlayerTrackSurface"])
+	}
+
+	parser.UseEnums(true)
+
+	tick, _ = parser.Next()
+	if surface, ok := tick["PlayerTrackSurface"].(irsdk.TrkLoc); !ok || !surface.InPitStall() {
+		t.Errorf("expected PlayerTrackSurface to be irsdk.TrkLoc InPitStall. received %v of type %T", tick["PlayerTrackSurface"], tick["PlayerTrackSurface"])
+	}
+
+	if _, ok := tick["Gear"].(int); !ok {
+		t.Errorf("expected Gear to remain an int. received %T", tick["Gear"])
+	}
+}
+
 func TestUpdateWhitelist(t *testing.T) {
 	t.Run("parser read buffer", func(t *testing.T) {
 		parser := NewParser(nil, nil, "Speed")
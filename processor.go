@@ -2,7 +2,7 @@ package ibt
 
 import (
 	"context"
-	"errors"
+	"fmt"
 	"sort"
 
 	"github.com/teamjorge/ibt/headers"
@@ -14,38 +14,217 @@ type Processor interface {
 	Whitelist() []string
 }
 
+// StubStarter can be implemented by processors that need to be notified before the first tick of a stub is processed.
+type StubStarter interface {
+	StartStub(stub Stub) error
+}
+
+// StubFinisher can be implemented by processors that need to be notified after the last tick of a stub was processed.
+//
+// This is useful for flushing batches or resetting state that should not carry over to the next stub.
+type StubFinisher interface {
+	FinishStub(stub Stub) error
+}
+
+// GroupFinisher can be implemented by processors that need to be notified once all stubs of a group were processed.
+//
+// This is useful for emitting summaries of a session.
+type GroupFinisher interface {
+	FinishGroup(group StubGroup) error
+}
+
+// ProcessOptions configures how telemetry is processed.
+type ProcessOptions struct {
+	// Maximum number of groups processed concurrently by ProcessParallel. Defaults to runtime.NumCPU().
+	Workers int
+	// Progress is called with the progress of the group as ticks are processed. Optional.
+	Progress func(Progress)
+	// Number of ticks between calls to Progress. Progress is always called after the last tick of a stub.
+	// Defaults to every tick.
+	ProgressInterval int
+	// ErrorPolicy determines how errors returned by processors are handled. Defaults to ErrorPolicyAbort.
+	ErrorPolicy ErrorPolicy
+	// Read bitfields as hex strings, such as "0x10040200", instead of typed irsdk bitfields.
+	// See Parser.UseLegacyBitFields.
+	LegacyBitFields bool
+	// Read known enum variables, such as PlayerTrackSurface, as typed irsdk enums instead of ints.
+	// See Parser.UseEnums.
+	Enums bool
+}
+
+// Process the telemetry of the stubs with the given processors.
+//
+// The stubs are sorted by time and processed in order. Processors that implement StubStarter, StubFinisher,
+// or GroupFinisher are notified at the start and end of each stub and at the end of the group.
+//
+// When the context is done, processing stops and the error of the context is returned, wrapped with the
+// filename and tick where processing stopped. Use errors.Is to check for context.Canceled or context.DeadlineExceeded.
+//
+// The first error returned by a processor stops processing and is returned as a *ProcessError.
+// See ProcessWithOptions for other ways of handling processor errors.
 func Process(ctx context.Context, stubs StubGroup, processors ...Processor) error {
+	return ProcessWithOptions(ctx, stubs, ProcessOptions{}, processors...)
+}
+
+// ProcessWithOptions processes the telemetry of the stubs in the same manner as Process, using the given options.
+//
+// Processor errors are handled according to the ErrorPolicy of the options. Unless processing is aborted,
+// the errors of all processors are returned as ProcessErrors after the group has been processed.
+func ProcessWithOptions(ctx context.Context, stubs StubGroup, opts ProcessOptions, processors ...Processor) error {
 	sort.Sort(stubs)
 
+	run := newProcessRun(stubs, opts, processors...)
+
 	for _, stub := range stubs {
-		if err := process(ctx, stub, processors...); err != nil {
+		if err := startStub(stub, processors...); err != nil {
+			return err
+		}
+
+		run.reporter.startStub(stub)
+		if err := process(ctx, stub, run); err != nil {
+			return err
+		}
+
+		if err := finishStub(stub, processors...); err != nil {
 			return err
 		}
 	}
 
+	if err := finishGroup(stubs, processors...); err != nil {
+		return err
+	}
+
+	if len(run.errs) > 0 {
+		return run.errs
+	}
+
 	return nil
 }
 
-func process(ctx context.Context, stub Stub, processors ...Processor) error {
+// processRun holds the state of processing a single group of stubs
+type processRun struct {
+	processors []Processor
+	opts       ProcessOptions
+	policy     ErrorPolicy
+	reporter   *progressReporter
+	// Processors that were disabled with ErrorPolicyDisableProcessor
+	disabled []bool
+	// Errors recorded by all policies other than ErrorPolicyAbort
+	errs ProcessErrors
+}
+
+func newProcessRun(stubs StubGroup, opts ProcessOptions, processors ...Processor) *processRun {
+	return &processRun{
+		processors: processors,
+		opts:       opts,
+		policy:     opts.ErrorPolicy,
+		reporter:   newProgressReporter(stubs, opts),
+		disabled:   make([]bool, len(processors)),
+	}
+}
+
+// handleError applies the error policy to the error returned by the processor at the given index.
+//
+// The error is returned when processing should be aborted. Skip is true when the tick should not be passed
+// to the remaining processors.
+func (r *processRun) handleError(err *ProcessError, idx int) (abort error, skip bool) {
+	if r.policy == ErrorPolicyAbort {
+		return err, false
+	}
+
+	r.errs = append(r.errs, err)
+
+	switch r.policy {
+	case ErrorPolicySkipTick:
+		return nil, true
+	case ErrorPolicyDisableProcessor:
+		r.disabled[idx] = true
+	}
+
+	return nil, false
+}
+
+// startStub notifies all StubStarter processors of the start of the stub
+func startStub(stub Stub, processors ...Processor) error {
+	for _, proc := range processors {
+		if starter, ok := proc.(StubStarter); ok {
+			if err := starter.StartStub(stub); err != nil {
+				return fmt.Errorf("failed to start stub %s: %w", stub.Filename(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// finishStub notifies all StubFinisher processors of the end of the stub
+func finishStub(stub Stub, processors ...Processor) error {
+	for _, proc := range processors {
+		if finisher, ok := proc.(StubFinisher); ok {
+			if err := finisher.FinishStub(stub); err != nil {
+				return fmt.Errorf("failed to finish stub %s: %w", stub.Filename(), err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// finishGroup notifies all GroupFinisher processors of the end of the group
+func finishGroup(stubs StubGroup, processors ...Processor) error {
+	for _, proc := range processors {
+		if finisher, ok := proc.(GroupFinisher); ok {
+			if err := finisher.FinishGroup(stubs); err != nil {
+				return fmt.Errorf("failed to finish group: %w", err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func process(ctx context.Context, stub Stub, run *processRun) error {
 	header := stub.header
 
-	whitelist := buildWhitelist(header.VarHeader, processors...)
+	whitelist := buildWhitelist(header.VarHeader, run.processors...)
+
+	reader, closeReader, err := stub.reader()
+	if err != nil {
+		return err
+	}
+	defer closeReader()
+
+	parser := NewParser(reader, header, whitelist...)
+	parser.UseLegacyBitFields(run.opts.LegacyBitFields)
+	parser.UseEnums(run.opts.Enums)
 
-	parser := NewParser(stub.r, header, whitelist...)
-	for {
+	for tick := 0; ; tick++ {
 		select {
 		case <-ctx.Done():
-			return errors.New("context cancelled")
+			return fmt.Errorf("processing of %s stopped at tick %d: %w", stub.Filename(), tick, ctx.Err())
 		default:
 		}
 
-		tick, hasNext := parser.Next()
-		for _, proc := range processors {
-			if err := proc.Process(tick.Filter(proc.Whitelist()...), hasNext, header.SessionInfo); err != nil {
-				return err
+		input, hasNext := parser.Next()
+		for idx, proc := range run.processors {
+			if run.disabled[idx] {
+				continue
+			}
+
+			if err := proc.Process(input.Filter(proc.Whitelist()...), hasNext, header.SessionInfo); err != nil {
+				abort, skip := run.handleError(&ProcessError{Filename: stub.Filename(), Tick: tick, Processor: unwrapProcessor(proc), Err: err}, idx)
+				if abort != nil {
+					return abort
+				}
+				// The last tick of a stub is always delivered, since processors rely on it to finish the stub
+				if skip && hasNext {
+					break
+				}
 			}
 		}
 
+		run.reporter.tick(hasNext)
+
 		if !hasNext {
 			break
 		}
@@ -69,8 +248,12 @@ func buildWhitelist(vars map[string]headers.VarHeader, processors ...Processor)
 //
 // Variables that are not found in the VarHeader will automatically be excluded.
 func parseAndValidateWhitelist(vars map[string]headers.VarHeader, processor Processor) []string {
-	whitelist := processor.Whitelist()
+	return validWhitelist(vars, processor.Whitelist())
+}
 
+// validWhitelist returns all vars when the whitelist is empty or contains *, and otherwise
+// only the variables of the whitelist that are found in the VarHeader.
+func validWhitelist(vars map[string]headers.VarHeader, whitelist []string) []string {
 	if len(whitelist) == 0 {
 		return headers.AvailableVars(vars)
 	}
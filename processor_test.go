@@ -3,11 +3,15 @@ package ibt
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"sort"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
 )
 
 type testProcessor struct {
@@ -33,6 +37,44 @@ func (t *testErrorProcessor) Process(input Tick, hasNext bool, session *headers.
 
 func (t *testErrorProcessor) Whitelist() []string { return []string{"LapCurrentLapTime"} }
 
+// testFailingProcessor returns an error for every tick with an index that is a multiple of every
+type testFailingProcessor struct {
+	every int
+	calls int
+}
+
+func (t *testFailingProcessor) Process(input Tick, hasNext bool, session *headers.Session) error {
+	t.calls++
+	if (t.calls-1)%t.every == 0 {
+		return fmt.Errorf("failed at call %d", t.calls)
+	}
+
+	return nil
+}
+
+func (t *testFailingProcessor) Whitelist() []string { return []string{"Lap"} }
+
+type testLifecycleProcessor struct {
+	testProcessor
+	events []string
+	err    error
+}
+
+func (t *testLifecycleProcessor) StartStub(stub Stub) error {
+	t.events = append(t.events, fmt.Sprintf("start %d", len(t.results)))
+	return t.err
+}
+
+func (t *testLifecycleProcessor) FinishStub(stub Stub) error {
+	t.events = append(t.events, fmt.Sprintf("finish %d", len(t.results)))
+	return nil
+}
+
+func (t *testLifecycleProcessor) FinishGroup(group StubGroup) error {
+	t.events = append(t.events, fmt.Sprintf("group %d", len(group)))
+	return nil
+}
+
 func TestProcess(t *testing.T) {
 	f, err := os.Open(".testing/valid_test_file.ibt")
 	if err != nil {
@@ -59,11 +101,11 @@ func TestProcess(t *testing.T) {
 		}
 
 		valueToCheck := proc.results[0]["LapCurrentLapTime"].(float32)
-		if valueToCheck != 37.678566 {
-			t.Errorf("expected value to check to be %f. got %f", 37.678566, valueToCheck)
+		if valueToCheck != 37.6619 {
+			t.Errorf("expected value to check to be %f. got %f", 37.6619, valueToCheck)
 		}
 
-		valueToCheck = proc.results[69]["LapCurrentLapTime"].(float32)
+		valueToCheck = proc.results[70]["LapCurrentLapTime"].(float32)
 		if valueToCheck != 38.828568 {
 			t.Errorf("expected value to check to be %f. got %f", 38.828568, valueToCheck)
 		}
@@ -77,14 +119,221 @@ func TestProcess(t *testing.T) {
 		}
 	})
 
+	t.Run("test Process() lifecycle hooks", func(t *testing.T) {
+		proc := testLifecycleProcessor{testProcessor: testProcessor{whitelist: []string{"Lap"}}}
+
+		if err := Process(context.Background(), StubGroup{stubs[0], stubs[0]}, &proc); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+		}
+
+		expected := []string{"start 0", "finish 390", "start 390", "finish 780", "group 2"}
+		if strings.Join(proc.events, ",") != strings.Join(expected, ",") {
+			t.Errorf("expected lifecycle events %v. received %v", expected, proc.events)
+		}
+	})
+
+	t.Run("test Process() lifecycle hook error", func(t *testing.T) {
+		proc := testLifecycleProcessor{err: errors.New("start error")}
+
+		err := Process(context.Background(), stubs, &proc)
+		if err == nil || !strings.Contains(err.Error(), "start error") {
+			t.Errorf("expected Process() to return the start error. received %v", err)
+		}
+
+		if len(proc.results) != 0 {
+			t.Errorf("expected no ticks to be processed after a start error. received %d", len(proc.results))
+		}
+	})
+
 	t.Run("test process() invalid file", func(t *testing.T) {
 		proc := testProcessor{whitelist: []string{"LapCurrentLapTime"}}
 
 		ctx, cancel := context.WithCancel(context.Background())
 		cancel()
 
-		if err := process(ctx, stubs[0], &proc); err == nil {
-			t.Errorf("expected process() to exit with a context done error")
+		err := process(ctx, stubs[0], newProcessRun(stubs, ProcessOptions{}, &proc))
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("expected process() to exit with a context done error. received %v", err)
+		}
+
+		if err != nil && !strings.Contains(err.Error(), ".testing/valid_test_file.ibt stopped at tick 0") {
+			t.Errorf("expected error to contain the filename and tick. received %v", err)
+		}
+	})
+
+	t.Run("test Process() deadline exceeded", func(t *testing.T) {
+		proc := testProcessor{whitelist: []string{"LapCurrentLapTime"}}
+
+		ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		defer cancel()
+
+		if err := Process(ctx, stubs, &proc); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("expected Process() to return a deadline exceeded error. received %v", err)
+		}
+	})
+
+	t.Run("test ProcessWithOptions() progress", func(t *testing.T) {
+		proc := testProcessor{whitelist: []string{"Lap"}}
+		progress := make([]Progress, 0)
+
+		opts := ProcessOptions{
+			Progress:         func(p Progress) { progress = append(progress, p) },
+			ProgressInterval: 100,
+		}
+
+		if err := ProcessWithOptions(context.Background(), StubGroup{stubs[0], stubs[0]}, opts, &proc); err != nil {
+			t.Errorf("expected ProcessWithOptions() to run without err. received error: %v", err)
+		}
+
+		// 3 reports at the interval and 1 at the end of each stub
+		if len(progress) != 8 {
+			t.Errorf("expected %d progress reports. received %d", 8, len(progress))
+			return
+		}
+
+		first := progress[0]
+		if first.StubTicks != 100 || first.StubTotal != 390 || first.Ticks != 100 || first.Total != 780 {
+			t.Errorf("expected first progress to be at tick 100 of 390. received %+v", first)
+		}
+
+		last := progress[len(progress)-1]
+		if last.StubTicks != 390 || last.Ticks != 780 || last.Percent() != 100 || last.StubPercent() != 100 {
+			t.Errorf("expected last progress to be complete. received %+v", last)
+		}
+
+		if progress[4].Filename != ".testing/valid_test_file.ibt" || progress[4].StubTicks != 100 || progress[4].Ticks != 490 {
+			t.Errorf("expected stub progress to reset for the second stub. received %+v", progress[4])
+		}
+	})
+
+	t.Run("test ProcessWithOptions() legacy bitfields", func(t *testing.T) {
+		proc := testProcessor{whitelist: []string{"SessionFlags"}}
+
+		if err := ProcessWithOptions(context.Background(), stubs, ProcessOptions{LegacyBitFields: true}, &proc); err != nil {
+			t.Errorf("expected ProcessWithOptions() to run without err. received error: %v", err)
+			return
+		}
+
+		if proc.results[0]["SessionFlags"] != "0x10040200" {
+			t.Errorf("expected SessionFlags to be %s. received %v", "0x10040200", proc.results[0]["SessionFlags"])
+		}
+	})
+
+	t.Run("test ProcessWithOptions() enums", func(t *testing.T) {
+		proc := testProcessor{whitelist: []string{"PlayerTrackSurface"}}
+
+		if err := ProcessWithOptions(context.Background(), stubs, ProcessOptions{Enums: true}, &proc); err != nil {
+			t.Errorf("expected ProcessWithOptions() to run without err. received error: %v", err)
+			return
+		}
+
+		if surface, ok := proc.results[0]["PlayerTrackSurface"].(irsdk.TrkLoc); !ok || !surface.InPitStall() {
+			t.Errorf("expected PlayerTrackSurface to be irsdk.TrkLoc InPitStall. received %v of type %T", proc.results[0]["PlayerTrackSurface"], proc.results[0]["PlayerTrackSurface"])
+		}
+	})
+}
+
+func TestErrorPolicy(t *testing.T) {
+	stubs, err := ParseStubs(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to parse stubs for testing file - %v", err)
+	}
+	defer stubs.Close()
+
+	t.Run("test ErrorPolicyAbort", func(t *testing.T) {
+		failing := &testFailingProcessor{every: 100}
+		proc := &testProcessor{whitelist: []string{"Lap"}}
+
+		err := ProcessWithOptions(context.Background(), stubs, ProcessOptions{}, failing, proc)
+
+		var processErr *ProcessError
+		if !errors.As(err, &processErr) {
+			t.Errorf("expected a *ProcessError to be returned. received %v", err)
+			return
+		}
+
+		if processErr.Filename != ".testing/valid_test_file.ibt" || processErr.Tick != 0 || processErr.Processor != failing {
+			t.Errorf("expected error to contain the stub, tick, and processor. received %+v", processErr)
+		}
+
+		if len(proc.results) != 0 {
+			t.Errorf("expected processing to stop at the first error. received %d ticks", len(proc.results))
+		}
+	})
+
+	t.Run("test ErrorPolicySkipTick", func(t *testing.T) {
+		failing := &testFailingProcessor{every: 100}
+		proc := &testProcessor{whitelist: []string{"Lap"}}
+
+		opts := ProcessOptions{ErrorPolicy: ErrorPolicySkipTick}
+		err := ProcessWithOptions(context.Background(), stubs, opts, failing, proc)
+
+		var report ProcessErrors
+		if !errors.As(err, &report) || len(report) != 4 || report[3].Tick != 300 {
+			t.Errorf("expected the errors of the skipped ticks to be reported. received %v", err)
+		}
+
+		// Ticks 0, 100, 200, and 300 are skipped
+		if failing.calls != 390 || len(proc.results) != 386 {
+			t.Errorf("expected %d ticks to be skipped. received %d processed", 4, len(proc.results))
+		}
+	})
+
+	t.Run("test ErrorPolicySkipTick last tick", func(t *testing.T) {
+		// Fails on the first and last ticks
+		failing := &testFailingProcessor{every: 389}
+		proc := &testProcessor{whitelist: []string{"Lap"}}
+
+		opts := ProcessOptions{ErrorPolicy: ErrorPolicySkipTick}
+		if err := ProcessWithOptions(context.Background(), stubs, opts, failing, proc); err == nil {
+			t.Error("expected the errors of the failing processor to be reported")
+		}
+
+		if len(proc.results) != 389 {
+			t.Errorf("expected only the first tick to be skipped. received %d ticks", len(proc.results))
+		}
+	})
+
+	t.Run("test ErrorPolicyDisableProcessor", func(t *testing.T) {
+		failing := &testFailingProcessor{every: 100}
+		proc := &testProcessor{whitelist: []string{"Lap"}}
+
+		opts := ProcessOptions{ErrorPolicy: ErrorPolicyDisableProcessor}
+		err := ProcessWithOptions(context.Background(), stubs, opts, failing, proc)
+
+		var report ProcessErrors
+		if !errors.As(err, &report) || len(report) != 1 || report[0].Processor != failing {
+			t.Errorf("expected the error that disabled the processor to be reported. received %v", err)
+		}
+
+		if failing.calls != 1 {
+			t.Errorf("expected failing processor to be disabled after the first error. received %d calls", failing.calls)
+		}
+
+		if len(proc.results) != 390 {
+			t.Errorf("expected other processors to receive all ticks. received %d", len(proc.results))
+		}
+	})
+
+	t.Run("test ErrorPolicyCollect", func(t *testing.T) {
+		failing := &testFailingProcessor{every: 100}
+		proc := &testProcessor{whitelist: []string{"Lap"}}
+
+		opts := ProcessOptions{ErrorPolicy: ErrorPolicyCollect}
+		err := ProcessWithOptions(context.Background(), stubs, opts, failing, proc)
+
+		var report ProcessErrors
+		if !errors.As(err, &report) {
+			t.Errorf("expected ProcessErrors to be returned. received %v", err)
+			return
+		}
+
+		if len(report) != 4 || report[3].Tick != 300 || len(report.Processor(failing)) != 4 || len(report.Processor(proc)) != 0 {
+			t.Errorf("expected errors of ticks 0, 100, 200, and 300 to be collected. received %v", report)
+		}
+
+		if len(proc.results) != 390 {
+			t.Errorf("expected all ticks to be processed. received %d", len(proc.results))
 		}
 	})
 }
@@ -0,0 +1,94 @@
+package ibt
+
+// Progress of processing a group of stubs.
+//
+// Totals are based on the DiskHeader.RecordCount of each stub and will be 0 for stubs without a disk header.
+type Progress struct {
+	// Filename of the stub currently being processed
+	Filename string
+	// Number of ticks processed of the current stub
+	StubTicks int
+	// Number of ticks that will be processed for the current stub
+	StubTotal int
+	// Number of ticks processed across all stubs of the group
+	Ticks int
+	// Number of ticks that will be processed across all stubs of the group
+	Total int
+}
+
+// StubPercent returns the percentage of the current stub that has been processed.
+func (p Progress) StubPercent() float64 { return percent(p.StubTicks, p.StubTotal) }
+
+// Percent returns the percentage of the group that has been processed.
+func (p Progress) Percent() float64 { return percent(p.Ticks, p.Total) }
+
+func percent(n, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+
+	return float64(n) / float64(total) * 100
+}
+
+// stubTicks returns the number of ticks a Parser will produce for the stub.
+func stubTicks(stub Stub) int {
+	if stub.header == nil || stub.header.DiskHeader == nil {
+		return 0
+	}
+
+	return stub.header.DiskHeader.RecordCount
+}
+
+// progressReporter keeps track of the Progress of a group and reports it at an interval.
+//
+// A nil progressReporter is valid and reports nothing.
+type progressReporter struct {
+	report   func(Progress)
+	interval int
+	progress Progress
+}
+
+// newProgressReporter creates a progressReporter for the stubs based on the options.
+//
+// Nil is returned when no Progress callback has been configured.
+func newProgressReporter(stubs StubGroup, opts ProcessOptions) *progressReporter {
+	if opts.Progress == nil {
+		return nil
+	}
+
+	r := &progressReporter{report: opts.Progress, interval: opts.ProgressInterval}
+	if r.interval <= 0 {
+		r.interval = 1
+	}
+
+	for _, stub := range stubs {
+		r.progress.Total += stubTicks(stub)
+	}
+
+	return r
+}
+
+// startStub resets the stub progress for the given stub.
+func (r *progressReporter) startStub(stub Stub) {
+	if r == nil {
+		return
+	}
+
+	r.progress.Filename = stub.Filename()
+	r.progress.StubTicks = 0
+	r.progress.StubTotal = stubTicks(stub)
+}
+
+// tick records a processed tick and reports the progress when the interval or the end of the stub is reached.
+func (r *progressReporter) tick(hasNext bool) {
+	if r == nil {
+		return
+	}
+
+	r.progress.StubTicks++
+	r.progress.Ticks++
+
+	if !hasNext || r.progress.StubTicks%r.interval == 0 {
+		r.report(r.progress)
+	}
+}
@@ -0,0 +1,305 @@
+package ibt
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/teamjorge/ibt/headers"
+)
+
+// Version of the index file format written by ScanDir
+const indexVersion = 1
+
+// ScanOptions configures how a directory is scanned with ScanDir.
+type ScanOptions struct {
+	// Maximum number of files of which the headers are parsed concurrently. Defaults to runtime.NumCPU().
+	Workers int
+	// Path of the index file. When set, the index is loaded before scanning and only files that are new or
+	// have changed since the previous scan are parsed. The updated index is written after scanning, only
+	// containing the files found under the scanned root. Optional.
+	IndexPath string
+}
+
+// IndexEntry is the metadata of a single ibt file found by ScanDir.
+type IndexEntry struct {
+	// Path of the ibt file
+	Path string `json:"path"`
+	// Size of the file in bytes when it was parsed
+	Size int64 `json:"size"`
+	// Modification time of the file when it was parsed
+	ModTime time.Time `json:"mod_time"`
+	// Error that occurred while parsing the file. Corrupt files are kept in the index so that they are not
+	// parsed again until they change.
+	Error string `json:"error,omitempty"`
+
+	// Unix timestamp of the start of the file
+	StartDate    int64  `json:"start_date,omitempty"`
+	RecordCount  int    `json:"record_count,omitempty"`
+	LapCount     int    `json:"lap_count,omitempty"`
+	TickRate     int    `json:"tick_rate,omitempty"`
+	Track        string `json:"track,omitempty"`
+	TrackConfig  string `json:"track_config,omitempty"`
+	EventType    string `json:"event_type,omitempty"`
+	Category     string `json:"category,omitempty"`
+	Car          string `json:"car,omitempty"`
+	Driver       string `json:"driver,omitempty"`
+	UserID       int    `json:"user_id,omitempty"`
+	SessionID    int    `json:"session_id,omitempty"`
+	SubSessionID int    `json:"sub_session_id,omitempty"`
+	Official     bool   `json:"official,omitempty"`
+}
+
+// Time when the file was created
+func (e IndexEntry) Time() time.Time { return time.Unix(e.StartDate, 0) }
+
+// ScanError is a file or directory that could not be scanned.
+type ScanError struct {
+	Path string
+	Err  error
+}
+
+func (e ScanError) Error() string { return fmt.Sprintf("failed to scan %s: %v", e.Path, e.Err) }
+
+// Unwrap returns the underlying error
+func (e ScanError) Unwrap() error { return e.Err }
+
+// ScanResult is the result of scanning a directory with ScanDir.
+type ScanResult struct {
+	// Entries of all valid ibt files, sorted by path
+	Entries []IndexEntry
+	// Files and directories that could not be scanned
+	Failed []ScanError
+	// Number of files that had their headers parsed. Unchanged files are taken from the index instead.
+	Parsed int
+}
+
+// Files returns the paths of all valid ibt files.
+func (r ScanResult) Files() []string {
+	files := make([]string, len(r.Entries))
+	for i, entry := range r.Entries {
+		files[i] = entry.Path
+	}
+
+	return files
+}
+
+// Stubs parses lazy stubs of all valid ibt files (see ParseLazyStubs).
+func (r ScanResult) Stubs() (StubGroup, error) { return ParseLazyStubs(r.Files()...) }
+
+// ScanDir recursively scans the root directory for ibt files and parses their headers concurrently.
+//
+// Files that cannot be parsed do not stop the scan, but are reported in the Failed field of the result.
+// An error is only returned when the root directory cannot be read, or the index cannot be loaded or written.
+func ScanDir(root string, opts ScanOptions) (ScanResult, error) {
+	var result ScanResult
+
+	index, err := readIndex(opts.IndexPath)
+	if err != nil {
+		return result, err
+	}
+
+	toParse := make([]IndexEntry, 0)
+	entries := make([]IndexEntry, 0)
+
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if path == root {
+				return err
+			}
+			result.Failed = append(result.Failed, ScanError{path, err})
+			if d != nil && d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if d.IsDir() || !strings.EqualFold(filepath.Ext(path), ".ibt") {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			result.Failed = append(result.Failed, ScanError{path, err})
+			return nil
+		}
+
+		if entry, ok := index[path]; ok && entry.Size == info.Size() && entry.ModTime.Equal(info.ModTime()) {
+			entries = append(entries, entry)
+			return nil
+		}
+
+		toParse = append(toParse, IndexEntry{Path: path, Size: info.Size(), ModTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return result, fmt.Errorf("failed to scan directory %s: %v", root, err)
+	}
+
+	parseIndexEntries(toParse, opts.Workers)
+	result.Parsed = len(toParse)
+	entries = append(entries, toParse...)
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Path < entries[j].Path })
+
+	result.Entries = make([]IndexEntry, 0, len(entries))
+	for _, entry := range entries {
+		if entry.Error != "" {
+			result.Failed = append(result.Failed, ScanError{entry.Path, errors.New(entry.Error)})
+			continue
+		}
+		result.Entries = append(result.Entries, entry)
+	}
+
+	if err := writeIndex(opts.IndexPath, entries); err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// parseIndexEntries parses the headers of the files of the entries with a bounded number of workers.
+func parseIndexEntries(entries []IndexEntry, workers int) {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if workers > len(entries) {
+		workers = len(entries)
+	}
+
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for idx := range jobs {
+				if err := parseIndexEntry(&entries[idx]); err != nil {
+					entries[idx].Error = err.Error()
+				}
+			}
+		}()
+	}
+
+	for idx := range entries {
+		jobs <- idx
+	}
+	close(jobs)
+
+	wg.Wait()
+}
+
+// parseIndexEntry parses the headers of the file of the entry and populates it's header fields.
+func parseIndexEntry(entry *IndexEntry) error {
+	f, err := os.Open(entry.Path)
+	if err != nil {
+		return fmt.Errorf("failed to open file %s for reading: %v", entry.Path, err)
+	}
+	defer f.Close()
+
+	header, err := headers.ParseHeaders(f)
+	if err != nil {
+		return fmt.Errorf("failed to parse headers for file %s - %v", entry.Path, err)
+	}
+
+	entry.TickRate = header.TelemetryHeader.TickRate
+
+	if header.DiskHeader != nil {
+		entry.StartDate = header.DiskHeader.StartDate
+		entry.RecordCount = header.DiskHeader.RecordCount
+		entry.LapCount = header.DiskHeader.LapCount
+	}
+
+	if session := header.SessionInfo; session != nil {
+		weekend := session.WeekendInfo
+		entry.Track = weekend.TrackDisplayName
+		entry.TrackConfig = weekend.TrackConfigName
+		entry.EventType = weekend.EventType
+		entry.Category = weekend.Category
+		entry.SessionID = weekend.SessionID
+		entry.SubSessionID = weekend.SubSessionID
+		entry.Official = weekend.Official == 1
+
+		if driver := session.GetDriver(); driver != nil {
+			entry.Car = driver.CarScreenName
+			entry.Driver = driver.UserName
+			entry.UserID = driver.UserID
+		}
+	}
+
+	return nil
+}
+
+// indexFile is the format of the index written by ScanDir
+type indexFile struct {
+	Version int          `json:"version"`
+	Entries []IndexEntry `json:"entries"`
+}
+
+// readIndex loads the entries of the index at the given path by the path of their file.
+//
+// An empty index is returned when no path is given or the index does not exist yet. Indexes of a different
+// version are ignored, causing all files to be parsed again.
+func readIndex(path string) (map[string]IndexEntry, error) {
+	index := make(map[string]IndexEntry)
+	if path == "" {
+		return index, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return index, nil
+		}
+		return nil, fmt.Errorf("failed to read index %s: %v", path, err)
+	}
+
+	var file indexFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse index %s: %v", path, err)
+	}
+
+	if file.Version != indexVersion {
+		return index, nil
+	}
+
+	for _, entry := range file.Entries {
+		index[entry.Path] = entry
+	}
+
+	return index, nil
+}
+
+// writeIndex writes the entries to the index at the given path. Nothing is written when no path is given.
+func writeIndex(path string, entries []IndexEntry) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(indexFile{Version: indexVersion, Entries: entries})
+	if err != nil {
+		return fmt.Errorf("failed to encode index: %v", err)
+	}
+
+	// Write to a temporary file first to avoid leaving a partially written index behind
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write index %s: %v", path, err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write index %s: %v", path, err)
+	}
+
+	return nil
+}
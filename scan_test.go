@@ -0,0 +1,136 @@
+package ibt
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// copyTestFile copies the testing file to the given path, creating any missing directories.
+func copyTestFile(t *testing.T, src, dst string) {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		t.Fatalf("failed to read testing file %s - %v", src, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		t.Fatalf("failed to create directory for %s - %v", dst, err)
+	}
+
+	if err := os.WriteFile(dst, data, 0o644); err != nil {
+		t.Fatalf("failed to write testing file %s - %v", dst, err)
+	}
+}
+
+func TestScanDir(t *testing.T) {
+	root := t.TempDir()
+
+	copyTestFile(t, ".testing/valid_test_file.ibt", filepath.Join(root, "mercedesw13", "first.ibt"))
+	copyTestFile(t, ".testing/valid_test_file.ibt", filepath.Join(root, "mercedesw13", "nested", "second.IBT"))
+	copyTestFile(t, ".testing/invalid_test_file.ibt", filepath.Join(root, "mx5", "invalid.ibt"))
+	copyTestFile(t, ".testing/empty_test_file.ibt", filepath.Join(root, "empty.ibt"))
+	copyTestFile(t, "README.md", filepath.Join(root, "README.md"))
+
+	indexPath := filepath.Join(t.TempDir(), "index.json")
+
+	t.Run("test ScanDir without index", func(t *testing.T) {
+		result, err := ScanDir(root, ScanOptions{Workers: 2})
+		if err != nil {
+			t.Errorf("expected ScanDir() to run without err. received error: %v", err)
+			return
+		}
+
+		if len(result.Entries) != 2 || len(result.Failed) != 2 || result.Parsed != 4 {
+			t.Errorf("expected 2 valid and 2 failed files. received %d valid, %d failed", len(result.Entries), len(result.Failed))
+			return
+		}
+
+		entry := result.Entries[0]
+		if entry.Path != filepath.Join(root, "mercedesw13", "first.ibt") || entry.Track != "Red Bull Ring" ||
+			entry.Car != "Mercedes-AMG W13 E Performance" || entry.RecordCount != 390 || entry.UserID != 450313 {
+			t.Errorf("expected the header fields of the testing file. received %+v", entry)
+		}
+
+		if result.Failed[0].Path != filepath.Join(root, "empty.ibt") || result.Failed[1].Path != filepath.Join(root, "mx5", "invalid.ibt") {
+			t.Errorf("expected the empty and invalid files to be reported. received %v", result.Failed)
+		}
+	})
+
+	t.Run("test ScanDir incremental index", func(t *testing.T) {
+		result, err := ScanDir(root, ScanOptions{IndexPath: indexPath})
+		if err != nil {
+			t.Errorf("expected ScanDir() to run without err. received error: %v", err)
+			return
+		}
+
+		if result.Parsed != 4 {
+			t.Errorf("expected all files to be parsed when the index does not exist. received %d", result.Parsed)
+		}
+
+		result, err = ScanDir(root, ScanOptions{IndexPath: indexPath})
+		if err != nil {
+			t.Errorf("expected ScanDir() to run without err. received error: %v", err)
+			return
+		}
+
+		if result.Parsed != 0 || len(result.Entries) != 2 || len(result.Failed) != 2 {
+			t.Errorf("expected unchanged files to be loaded from the index. received %d parsed", result.Parsed)
+		}
+
+		if result.Entries[1].Track != "Red Bull Ring" {
+			t.Errorf("expected header fields to be loaded from the index. received %+v", result.Entries[1])
+		}
+
+		changed := filepath.Join(root, "mercedesw13", "first.ibt")
+		modTime := time.Now().Add(time.Hour)
+		if err := os.Chtimes(changed, modTime, modTime); err != nil {
+			t.Fatalf("failed to change modification time of %s - %v", changed, err)
+		}
+		os.Remove(filepath.Join(root, "empty.ibt"))
+
+		result, err = ScanDir(root, ScanOptions{IndexPath: indexPath})
+		if err != nil {
+			t.Errorf("expected ScanDir() to run without err. received error: %v", err)
+			return
+		}
+
+		if result.Parsed != 1 || len(result.Failed) != 1 {
+			t.Errorf("expected only the changed file to be parsed and the removed file to be dropped. received %d parsed, %v failed", result.Parsed, result.Failed)
+		}
+	})
+
+	t.Run("test ScanResult Stubs", func(t *testing.T) {
+		result, err := ScanDir(root, ScanOptions{IndexPath: indexPath})
+		if err != nil {
+			t.Errorf("expected ScanDir() to run without err. received error: %v", err)
+			return
+		}
+
+		stubs, err := result.Stubs()
+		if err != nil {
+			t.Errorf("expected Stubs() to run without err. received error: %v", err)
+			return
+		}
+		defer stubs.Close()
+
+		if len(stubs) != 2 || stubs[0].Headers().DiskHeader.RecordCount != 390 {
+			t.Errorf("expected stubs for the valid files. received %d", len(stubs))
+		}
+	})
+
+	t.Run("test ScanDir invalid root", func(t *testing.T) {
+		if _, err := ScanDir(filepath.Join(root, "missing"), ScanOptions{}); err == nil {
+			t.Error("expected ScanDir() to return an error for a missing root")
+		}
+	})
+
+	t.Run("test ScanDir invalid index", func(t *testing.T) {
+		invalidIndex := filepath.Join(t.TempDir(), "index.json")
+		os.WriteFile(invalidIndex, []byte("not json"), 0o644)
+
+		if _, err := ScanDir(root, ScanOptions{IndexPath: invalidIndex}); err == nil {
+			t.Error("expected ScanDir() to return an error for an invalid index")
+		}
+	})
+}
@@ -0,0 +1,275 @@
+package ibt
+
+import (
+	"sort"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/metric"
+)
+
+// LapSectorTimes are the sector times of a single lap.
+type LapSectorTimes struct {
+	// Lap number, as given by the Lap variable
+	Lap int
+	// Sector times indexed by sector. Sectors that were not timed are 0.
+	Sectors []metric.LapTime
+	// Whether every sector of the lap was timed
+	Complete bool
+}
+
+// Total of all timed sectors of the lap
+func (l LapSectorTimes) Total() metric.LapTime {
+	var total metric.LapTime
+	for _, sector := range l.Sectors {
+		total += sector
+	}
+
+	return total
+}
+
+// BestSector is the fastest time recorded for a sector.
+type BestSector struct {
+	// Index of the sector
+	Sector int
+	// Lap number on which the time was set
+	Lap int
+	// Time of the sector
+	Time metric.LapTime
+}
+
+// SectorTimes is the result of a SectorProcessor.
+type SectorTimes struct {
+	// Sector times of every lap that had at least one sector timed
+	Laps []LapSectorTimes
+	// Best time of each sector. Sectors that were never timed will have a Time of 0.
+	Best []BestSector
+	// Sum of the best sectors. This will be 0 if not all sectors were timed.
+	TheoreticalBest metric.LapTime
+}
+
+// SectorProcessor calculates sector times using the SplitTimeInfo of the session.
+//
+// Sector boundary crossings are found with LapDistPct and the crossing time is interpolated between
+// the SessionTime of the ticks on either side of the boundary. Timing is reset at the end of each stub
+// and whenever the car moves backwards (such as a tow or reset), as sectors can not be timed accurately.
+//
+// A single SectorProcessor can be used for all stubs of a StubGroup to find the best sectors across a session.
+type SectorProcessor struct {
+	// Start percentage of each sector, where the first sector always starts at 0
+	starts []float64
+
+	laps    []LapSectorTimes
+	current *LapSectorTimes
+
+	// Whether a previous tick is available for the current stub
+	tracking bool
+	prevPct  float64
+	prevTime float64
+
+	sector      int
+	sectorStart float64
+	// Whether sectorStart is a real boundary crossing
+	sectorTimed bool
+}
+
+// NewSectorProcessor creates a new SectorProcessor.
+func NewSectorProcessor() *SectorProcessor {
+	return &SectorProcessor{laps: make([]LapSectorTimes, 0)}
+}
+
+// Whitelist of the variables required for sector timing
+func (s *SectorProcessor) Whitelist() []string { return []string{"Lap", "LapDistPct", "SessionTime"} }
+
+// Process a tick of telemetry and record any sector boundaries that were crossed since the previous tick.
+func (s *SectorProcessor) Process(input Tick, hasNext bool, session *headers.Session) error {
+	if s.starts == nil {
+		s.starts = sectorStarts(session)
+	}
+
+	lap, err := GetTickValue[int](input, "Lap")
+	if err != nil {
+		return err
+	}
+
+	lapDistPct, err := GetTickValue[float32](input, "LapDistPct")
+	if err != nil {
+		return err
+	}
+
+	sessionTime, err := GetTickValue[float64](input, "SessionTime")
+	if err != nil {
+		return err
+	}
+
+	s.processPosition(lap, float64(lapDistPct), sessionTime)
+
+	if !hasNext {
+		s.finishLap()
+		s.tracking = false
+	}
+
+	return nil
+}
+
+// processPosition records the sector crossings between the previous position and the given position.
+func (s *SectorProcessor) processPosition(lap int, pct, sessionTime float64) {
+	// LapDistPct is negative when the car is not on track
+	if pct < 0 {
+		s.tracking = false
+		return
+	}
+
+	if !s.tracking {
+		s.startTracking(lap, pct, sessionTime)
+		return
+	}
+
+	prevPct := s.prevPct
+	// A large decrease indicates that the start/finish line was crossed
+	if prevPct-pct > 0.5 {
+		prevPct -= 1
+	}
+
+	if pct < prevPct {
+		s.startTracking(lap, pct, sessionTime)
+		return
+	}
+
+	prevTime := s.prevTime
+
+	for {
+		next := (s.sector + 1) % len(s.starts)
+
+		// Find the first occurrence of the boundary after the previous position
+		boundary := s.starts[next]
+		for boundary <= prevPct {
+			boundary++
+		}
+		for boundary-1 > prevPct {
+			boundary--
+		}
+
+		if boundary > pct {
+			break
+		}
+
+		crossing := prevTime + (boundary-prevPct)/(pct-prevPct)*(sessionTime-prevTime)
+
+		if s.sectorTimed {
+			s.current.Sectors[s.sector] = metric.LapTime(crossing - s.sectorStart)
+		}
+
+		if next == 0 {
+			s.finishLap()
+			s.current = s.newLap(lap)
+		}
+
+		s.sector = next
+		s.sectorStart = crossing
+		s.sectorTimed = true
+
+		prevPct, prevTime = boundary, crossing
+	}
+
+	s.prevPct, s.prevTime = pct, sessionTime
+}
+
+// startTracking starts tracking from the given position without timing the current sector.
+func (s *SectorProcessor) startTracking(lap int, pct, sessionTime float64) {
+	s.finishLap()
+
+	s.tracking = true
+	s.prevPct, s.prevTime = pct, sessionTime
+	s.sectorTimed = false
+
+	s.sector = 0
+	for i, start := range s.starts {
+		if pct >= start {
+			s.sector = i
+		}
+	}
+
+	s.current = s.newLap(lap)
+}
+
+func (s *SectorProcessor) newLap(lap int) *LapSectorTimes {
+	return &LapSectorTimes{Lap: lap, Sectors: make([]metric.LapTime, len(s.starts))}
+}
+
+// finishLap adds the current lap to the results if any of it's sectors were timed.
+func (s *SectorProcessor) finishLap() {
+	if s.current == nil {
+		return
+	}
+
+	lap := *s.current
+	s.current = nil
+
+	lap.Complete = true
+	timed := false
+	for _, sector := range lap.Sectors {
+		if sector > 0 {
+			timed = true
+		} else {
+			lap.Complete = false
+		}
+	}
+
+	if timed {
+		s.laps = append(s.laps, lap)
+	}
+}
+
+// Result of the processed sector times, including the best sectors and theoretical best lap.
+func (s *SectorProcessor) Result() SectorTimes {
+	result := SectorTimes{
+		Laps: make([]LapSectorTimes, len(s.laps)),
+		Best: make([]BestSector, len(s.starts)),
+	}
+	copy(result.Laps, s.laps)
+
+	for i := range result.Best {
+		result.Best[i].Sector = i
+	}
+
+	for _, lap := range s.laps {
+		for i, sector := range lap.Sectors {
+			if sector > 0 && (result.Best[i].Time == 0 || sector < result.Best[i].Time) {
+				result.Best[i].Lap = lap.Lap
+				result.Best[i].Time = sector
+			}
+		}
+	}
+
+	for _, best := range result.Best {
+		if best.Time == 0 {
+			return result
+		}
+	}
+
+	for _, best := range result.Best {
+		result.TheoreticalBest += best.Time
+	}
+
+	return result
+}
+
+// sectorStarts retrieves the sorted sector start percentages from the session.
+//
+// A single sector starting at 0 is used when no sectors are available.
+func sectorStarts(session *headers.Session) []float64 {
+	starts := []float64{0}
+
+	if session == nil {
+		return starts
+	}
+
+	for _, sector := range session.SplitTimeInfo.Sectors {
+		if sector.SectorStartPct > 0 && sector.SectorStartPct < 1 {
+			starts = append(starts, sector.SectorStartPct)
+		}
+	}
+	sort.Float64s(starts)
+
+	return starts
+}
@@ -0,0 +1,149 @@
+package ibt
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/metric"
+)
+
+func TestSectorProcessor(t *testing.T) {
+	session := &headers.Session{
+		SplitTimeInfo: headers.SplitTimeInfo{Sectors: []headers.Sectors{
+			{SectorNum: 0, SectorStartPct: 0},
+			{SectorNum: 1, SectorStartPct: 0.5},
+		}},
+	}
+
+	// Creates a tick for a car that started at 10% of lap 1 and covers 5% of the lap every second.
+	// The first sector boundary is therefore crossed at 8 seconds and every 10 seconds thereafter.
+	createTick := func(second int) Tick {
+		distance := 0.1 + 0.05*float64(second)
+		return Tick{
+			"Lap":         1 + int(math.Floor(distance)),
+			"LapDistPct":  float32(distance - math.Floor(distance)),
+			"SessionTime": float64(second),
+		}
+	}
+
+	equalLapTime := func(a, b metric.LapTime) bool { return math.Abs(float64(a-b)) < 0.001 }
+
+	t.Run("test SectorProcessor laps", func(t *testing.T) {
+		processor := NewSectorProcessor()
+
+		for second := 0; second <= 50; second++ {
+			if err := processor.Process(createTick(second), second < 50, session); err != nil {
+				t.Errorf("expected Process() to run without err. received error: %v", err)
+				return
+			}
+		}
+
+		result := processor.Result()
+
+		if len(result.Laps) != 3 {
+			t.Errorf("expected %d laps to be timed. received %+v", 3, result.Laps)
+			return
+		}
+
+		first, second, third := result.Laps[0], result.Laps[1], result.Laps[2]
+
+		if first.Lap != 1 || first.Complete || first.Sectors[0] != 0 || !equalLapTime(first.Sectors[1], 10) {
+			t.Errorf("expected lap 1 to only have the second sector timed. received %+v", first)
+		}
+
+		if second.Lap != 2 || !second.Complete || !equalLapTime(second.Total(), 20) {
+			t.Errorf("expected lap 2 to be complete with a time of %d. received %+v", 20, second)
+		}
+
+		if third.Lap != 3 || third.Complete || !equalLapTime(third.Sectors[0], 10) {
+			t.Errorf("expected lap 3 to only have the first sector timed. received %+v", third)
+		}
+
+		if !equalLapTime(result.TheoreticalBest, 20) {
+			t.Errorf("expected theoretical best to be %d. received %f", 20, result.TheoreticalBest)
+		}
+
+		if result.Best[0].Lap != 2 || result.Best[1].Lap != 1 {
+			t.Errorf("expected best sectors to be set on laps 2 and 1. received %+v", result.Best)
+		}
+	})
+
+	t.Run("test SectorProcessor interpolation", func(t *testing.T) {
+		processor := NewSectorProcessor()
+
+		ticks := []Tick{
+			{"Lap": 1, "LapDistPct": float32(0.4), "SessionTime": float64(10)},
+			{"Lap": 1, "LapDistPct": float32(0.6), "SessionTime": float64(12)},
+			{"Lap": 1, "LapDistPct": float32(0.9), "SessionTime": float64(20)},
+			{"Lap": 2, "LapDistPct": float32(0.1), "SessionTime": float64(24)},
+		}
+
+		for i, tick := range ticks {
+			if err := processor.Process(tick, i < len(ticks)-1, session); err != nil {
+				t.Errorf("expected Process() to run without err. received error: %v", err)
+				return
+			}
+		}
+
+		result := processor.Result()
+		if len(result.Laps) != 1 || !equalLapTime(result.Laps[0].Sectors[1], 11) {
+			t.Errorf("expected second sector to be interpolated from %d to %d seconds. received %+v", 11, 22, result.Laps)
+		}
+	})
+
+	t.Run("test SectorProcessor reset", func(t *testing.T) {
+		processor := NewSectorProcessor()
+
+		ticks := []Tick{
+			{"Lap": 1, "LapDistPct": float32(0.4), "SessionTime": float64(10)},
+			{"Lap": 1, "LapDistPct": float32(0.6), "SessionTime": float64(12)},
+			{"Lap": 1, "LapDistPct": float32(0.3), "SessionTime": float64(14)},
+			{"Lap": 1, "LapDistPct": float32(0.9), "SessionTime": float64(20)},
+			{"Lap": 2, "LapDistPct": float32(0.1), "SessionTime": float64(24)},
+		}
+
+		for i, tick := range ticks {
+			if err := processor.Process(tick, i < len(ticks)-1, session); err != nil {
+				t.Errorf("expected Process() to run without err. received error: %v", err)
+				return
+			}
+		}
+
+		// Timing restarts at 0.3, meaning the boundary crossing at 16 seconds starts the next timed sector
+		result := processor.Result()
+		if len(result.Laps) != 1 || result.Laps[0].Sectors[0] != 0 || !equalLapTime(result.Laps[0].Sectors[1], 6) {
+			t.Errorf("expected only the second sector to be timed after moving backwards. received %+v", result.Laps)
+		}
+	})
+
+	t.Run("test SectorProcessor missing variable", func(t *testing.T) {
+		processor := NewSectorProcessor()
+
+		if err := processor.Process(Tick{"Lap": 1}, false, session); err == nil {
+			t.Error("expected Process() to return an error for missing variables")
+		}
+	})
+
+	t.Run("test SectorProcessor valid file", func(t *testing.T) {
+		stubs, err := ParseStubs(".testing/valid_test_file.ibt")
+		if err != nil {
+			t.Errorf("failed to parse stubs for testing file - %v", err)
+			return
+		}
+		defer stubs.Close()
+
+		processor := NewSectorProcessor()
+		if err := Process(context.Background(), stubs, processor); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+			return
+		}
+
+		// The testing file does not cross any sector boundaries
+		result := processor.Result()
+		if len(result.Best) != 3 || len(result.Laps) != 0 || result.TheoreticalBest != 0 {
+			t.Errorf("expected 3 untimed sectors. received %+v", result)
+		}
+	})
+}
@@ -14,6 +14,10 @@ import (
 //
 // Stubs are used for initial parsing of ibt files and their metadata. This can be useful
 // to make decisions regarding which files should have their telemetry parsed.
+//
+// A stub can either keep it's file open (see ParseStubs), or only open it when the telemetry is read
+// (see ParseLazyStubs). Stubs that are not open are opened on demand by Process and LoadChannels and
+// closed again once they are done.
 type Stub struct {
 	filepath string
 	header   *headers.Header
@@ -30,8 +34,55 @@ func (stub *Stub) Open() (err error) {
 	return nil
 }
 
-// Close the stub reader
-func (stub *Stub) Close() error { return stub.r.Close() }
+// Close the stub reader.
+//
+// Closing a stub that was never opened, or has already been closed, does nothing.
+func (stub *Stub) Close() error {
+	if stub.r == nil {
+		return nil
+	}
+
+	err := stub.r.Close()
+	stub.r = nil
+	if errors.Is(err, os.ErrClosed) {
+		return nil
+	}
+
+	return err
+}
+
+// IsOpen returns true if the stub reader is open
+func (stub *Stub) IsOpen() bool { return stub.r != nil }
+
+// Parser returns a Parser of the stub for the given whitelist, opening the stub if it is not open.
+//
+// The stub should be closed once the parser is no longer used.
+func (stub *Stub) Parser(whitelist ...string) (*Parser, error) {
+	if stub.r == nil {
+		if err := stub.Open(); err != nil {
+			return nil, err
+		}
+	}
+
+	return NewParser(stub.r, stub.header, whitelist...), nil
+}
+
+// reader returns the reader of the stub, opening the underlying file if the stub is not open.
+//
+// The returned function closes the file if it was opened by reader and does nothing otherwise, leaving
+// stubs that were already open as they were.
+func (stub *Stub) reader() (headers.Reader, func() error, error) {
+	if stub.r != nil {
+		return stub.r, func() error { return nil }, nil
+	}
+
+	f, err := os.Open(stub.Filename())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open stub file %s for reading: %v", stub.Filename(), err)
+	}
+
+	return f, f.Close, nil
+}
 
 // Filename where the stub originated from
 func (stub *Stub) Filename() string { return stub.filepath }
@@ -63,8 +114,8 @@ type StubGroup []Stub
 func (sg StubGroup) Close() error {
 	errs := make([]error, 0)
 
-	for _, stub := range sg {
-		if err := stub.Close(); err != nil {
+	for i := range sg {
+		if err := sg[i].Close(); err != nil {
 			errs = append(errs, err)
 		}
 	}
@@ -77,11 +128,22 @@ func (sg StubGroup) Close() error {
 }
 
 // ParseStubs will create a stub for each of the given files by parsing their headers.
-func ParseStubs(files ...string) (StubGroup, error) {
+//
+// The file of every stub is kept open until the stub is closed. Use ParseLazyStubs when parsing
+// a large number of files.
+func ParseStubs(files ...string) (StubGroup, error) { return parseStubs(false, files...) }
+
+// ParseLazyStubs will create a stub for each of the given files by parsing their headers.
+//
+// The file of every stub is closed as soon as it's headers are parsed and opened again when it's
+// telemetry is processed, meaning any number of files can be parsed without running out of file handles.
+func ParseLazyStubs(files ...string) (StubGroup, error) { return parseStubs(true, files...) }
+
+func parseStubs(lazy bool, files ...string) (StubGroup, error) {
 	stubs := make(StubGroup, 0)
 
 	for _, file := range files {
-		stub, err := parseStub(file)
+		stub, err := parseStub(file, lazy)
 		if err != nil {
 			stubs.Close()
 			return stubs, err
@@ -94,7 +156,9 @@ func ParseStubs(files ...string) (StubGroup, error) {
 }
 
 // parseStub will create a stub from the given file by parsing it's headers.
-func parseStub(filename string) (Stub, error) {
+//
+// The file is closed after parsing for lazy stubs.
+func parseStub(filename string, lazy bool) (Stub, error) {
 	var stub Stub
 
 	f, err := os.Open(filename)
@@ -104,9 +168,18 @@ func parseStub(filename string) (Stub, error) {
 
 	header, err := headers.ParseHeaders(f)
 	if err != nil {
+		f.Close()
 		return stub, fmt.Errorf("failed to parse headers for file %s - %v", filename, err)
 	}
 
+	if lazy {
+		if err := f.Close(); err != nil {
+			return stub, fmt.Errorf("failed to close file %s: %v", filename, err)
+		}
+
+		return Stub{filename, header, nil}, nil
+	}
+
 	return Stub{filename, header, f}, nil
 }
 
@@ -2,6 +2,8 @@ package ibt
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"os"
 	"reflect"
 	"sort"
@@ -11,6 +13,12 @@ import (
 	"github.com/teamjorge/ibt/headers"
 )
 
+type testCloseErrorReader struct {
+	headers.Reader
+}
+
+func (testCloseErrorReader) Close() error { return errors.New("unit test close error") }
+
 func TestStubs(t *testing.T) {
 	f, err := os.Open(".testing/valid_test_file.ibt")
 	if err != nil {
@@ -86,6 +94,33 @@ func TestStubs(t *testing.T) {
 		}
 	})
 
+	t.Run("stubs Parser()", func(t *testing.T) {
+		stub := Stub{filepath: ".testing/valid_test_file.ibt", header: header}
+		defer stub.Close()
+
+		parser, err := stub.Parser("Lap")
+		if err != nil {
+			t.Errorf("did not expect an error when creating a parser for file %s. received: %v", ".testing/valid_test_file.ibt", err)
+			return
+		}
+
+		if !stub.IsOpen() {
+			t.Error("expected Parser() to open the stub")
+		}
+
+		if tick, _ := parser.Next(); tick == nil || tick["Lap"] == nil {
+			t.Errorf("expected the first tick to contain Lap. received %v", tick)
+		}
+	})
+
+	t.Run("stubs Parser() invalid file", func(t *testing.T) {
+		stub := Stub{filepath: ".testing/disappear_here.ibt", header: header}
+
+		if _, err := stub.Parser(); err == nil {
+			t.Errorf("expected an error when creating a parser for a non-existent file %s", ".testing/disappear_here.ibt")
+		}
+	})
+
 	t.Run("stubs Open() invalid file", func(t *testing.T) {
 		stub := Stub{filepath: ".testing/disappear_here.ibt"}
 
@@ -97,7 +132,7 @@ func TestStubs(t *testing.T) {
 
 func TestParseStubs(t *testing.T) {
 	t.Run("test parseStub valid file", func(t *testing.T) {
-		parsedStub, err := parseStub(".testing/valid_test_file.ibt")
+		parsedStub, err := parseStub(".testing/valid_test_file.ibt", false)
 		if err != nil {
 			t.Errorf("unexpected error received from parseStub(): %v", err)
 		}
@@ -124,14 +159,14 @@ func TestParseStubs(t *testing.T) {
 	})
 
 	t.Run("test parseStub invalid file", func(t *testing.T) {
-		_, err := parseStub(".testing/invalid_test_file.ibt")
+		_, err := parseStub(".testing/invalid_test_file.ibt", false)
 		if err == nil {
 			t.Error("expected an error from parseStub() when reading an invalid file")
 		}
 	})
 
 	t.Run("test parseStub non-existent file", func(t *testing.T) {
-		_, err := parseStub(".testing/disappear_here.ibt")
+		_, err := parseStub(".testing/disappear_here.ibt", false)
 		if err == nil {
 			t.Error("expected an error from parseStub() when reading an invalid file")
 		}
@@ -182,6 +217,62 @@ func TestParseStubs(t *testing.T) {
 			t.Errorf("expected the parsed stub to have a BufOffset of %d. received: %d", 53764, parsedStubs[0].header.TelemetryHeader.BufOffset)
 		}
 	})
+
+	t.Run("test ParseLazyStubs() valid file", func(t *testing.T) {
+		parsedStubs, err := ParseLazyStubs(".testing/valid_test_file.ibt", ".testing/valid_test_file.ibt")
+		if err != nil {
+			t.Errorf("unexpected error received from ParseLazyStubs(): %v", err)
+		}
+
+		if len(parsedStubs) != 2 || parsedStubs[1].header.DiskHeader.StartDate != 1719258336 {
+			t.Errorf("expected %d stubs to be parsed with their headers. parsed stubs: %v", 2, parsedStubs)
+		}
+
+		for _, stub := range parsedStubs {
+			if stub.IsOpen() {
+				t.Errorf("expected lazy stub %s to be closed after parsing", stub.Filename())
+			}
+		}
+
+		if err := parsedStubs.Close(); err != nil {
+			t.Errorf("expected closing lazy stubs to do nothing. received: %v", err)
+		}
+	})
+
+	t.Run("test ParseLazyStubs() process and load on demand", func(t *testing.T) {
+		parsedStubs, err := ParseLazyStubs(".testing/valid_test_file.ibt")
+		if err != nil {
+			t.Errorf("unexpected error received from ParseLazyStubs(): %v", err)
+			return
+		}
+
+		proc := testProcessor{whitelist: []string{"Lap"}}
+		if err := Process(context.Background(), parsedStubs, &proc); err != nil {
+			t.Errorf("expected Process() to open the lazy stub. received error: %v", err)
+		}
+
+		if len(proc.results) != 390 {
+			t.Errorf("expected %d ticks to be processed. received %d", 390, len(proc.results))
+		}
+
+		frame, err := LoadChannels(parsedStubs[0], "Lap")
+		if err != nil || frame.Len != 390 {
+			t.Errorf("expected LoadChannels() to open the lazy stub. received error: %v", err)
+		}
+
+		if parsedStubs[0].IsOpen() {
+			t.Error("expected lazy stub to be closed again after processing")
+		}
+	})
+
+	t.Run("test Process() lazy stub missing file", func(t *testing.T) {
+		parsedStubs, _ := ParseLazyStubs(".testing/valid_test_file.ibt")
+		parsedStubs[0].filepath = ".testing/disappear_here.ibt"
+
+		if err := Process(context.Background(), parsedStubs, &testProcessor{}); err == nil {
+			t.Error("expected Process() to return an error when the lazy stub cannot be opened")
+		}
+	})
 }
 
 func TestGroupTestSessionStubs(t *testing.T) {
@@ -473,16 +564,35 @@ func TestStubGroupClose(t *testing.T) {
 			t.Errorf("expected stub group to close without error. received: %v", err)
 		}
 
-		if err := stubGroup[0].r.Close(); err == nil {
+		if err := f1.Close(); err == nil {
 			t.Errorf("expected stub 0 to be closed")
 		}
 
-		if err := stubGroup[1].r.Close(); err == nil {
+		if err := f2.Close(); err == nil {
 			t.Errorf("expected stub 0 to be closed")
 		}
+
+		if stubGroup[0].IsOpen() || stubGroup[1].IsOpen() {
+			t.Errorf("expected stubs to no longer be open")
+		}
+
+		if err := stubGroup.Close(); err != nil {
+			t.Errorf("expected closing an already closed stub group to do nothing. received: %v", err)
+		}
 	})
 
-	t.Run("test close with error", func(t *testing.T) {
+	t.Run("test close never opened", func(t *testing.T) {
+		stubGroup := StubGroup{
+			Stub{filepath: "5.ibt"},
+			Stub{filepath: "3.ibt"},
+		}
+
+		if err := stubGroup.Close(); err != nil {
+			t.Errorf("expected closing stubs that were never opened to do nothing. received: %v", err)
+		}
+	})
+
+	t.Run("test close already closed file", func(t *testing.T) {
 		f1, err := os.Open(".testing/empty_test_file.ibt")
 		if err != nil {
 			t.Errorf("failed to open test file %v", err)
@@ -499,14 +609,19 @@ func TestStubGroupClose(t *testing.T) {
 			Stub{filepath: "3.ibt", r: f2},
 		}
 
-		err = stubGroup.Close()
-		if err == nil {
-			t.Error("expected stub group to close with an error")
+		if err := stubGroup.Close(); err != nil {
+			t.Errorf("expected closing an already closed file to do nothing. received: %v", err)
 		}
+	})
 
-		if err.Error() != "close .testing/empty_test_file.ibt: file already closed" {
-			t.Errorf("expected error message to be %s. received: %s",
-				"close .testing/empty_test_file.ibt: file already closed", err.Error())
+	t.Run("test close with error", func(t *testing.T) {
+		stubGroup := StubGroup{
+			Stub{filepath: "5.ibt", r: testCloseErrorReader{}},
+		}
+
+		err := stubGroup.Close()
+		if err == nil || err.Error() != "unit test close error" {
+			t.Errorf("expected error message to be %s. received: %v", "unit test close error", err)
 		}
 	})
 }
@@ -538,11 +653,11 @@ func TestCloseAllStubs(t *testing.T) {
 			t.Errorf("expected stub group to close without error. received: %v", err)
 		}
 
-		if err := stubGroups[0][0].r.Close(); err == nil {
+		if err := f1.Close(); err == nil {
 			t.Errorf("expected stub 0 to be closed")
 		}
 
-		if err := stubGroups[1][0].r.Close(); err == nil {
+		if err := f2.Close(); err == nil {
 			t.Errorf("expected stub 0 to be closed")
 		}
 	})
@@ -553,29 +668,19 @@ func TestCloseAllStubs(t *testing.T) {
 			t.Errorf("failed to open test file %v", err)
 		}
 		defer f1.Close()
-		f2, err := os.Open(".testing/empty_test_file.ibt")
-		if err != nil {
-			t.Errorf("failed to open test file %v", err)
-		}
-		f2.Close()
 
 		stubGroups := []StubGroup{
 			{
 				Stub{filepath: "5.ibt", r: f1},
 			},
 			{
-				Stub{filepath: "3.ibt", r: f2},
+				Stub{filepath: "3.ibt", r: testCloseErrorReader{}},
 			},
 		}
 
 		err = CloseAllStubs(stubGroups)
-		if err == nil {
-			t.Error("expected stub group to close with an error")
-		}
-
-		if err.Error() != "close .testing/empty_test_file.ibt: file already closed" {
-			t.Errorf("expected error message to be %s. received: %s",
-				"close .testing/empty_test_file.ibt: file already closed", err.Error())
+		if err == nil || err.Error() != "unit test close error" {
+			t.Errorf("expected error message to be %s. received: %v", "unit test close error", err)
 		}
 	})
 }
@@ -3,6 +3,9 @@ package ibt
 import (
 	"fmt"
 	"reflect"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
 )
 
 // Tick is a single instance of telemetry data
@@ -10,7 +13,12 @@ type Tick map[string]interface{}
 
 // TickValueType is an interface containing all possible types for the value of a telemetry variable
 type TickValueType interface {
-	uint8 | []uint8 | bool | []bool | int | []int | string | []string | float32 | []float32 | float64 | []float64
+	uint8 | []uint8 | bool | []bool | int | []int | string | []string | float32 | []float32 | float64 | []float64 |
+		irsdk.SessionFlags | []irsdk.SessionFlags | irsdk.EngineWarnings | []irsdk.EngineWarnings |
+		irsdk.CameraState | []irsdk.CameraState | irsdk.PitSvFlags | []irsdk.PitSvFlags |
+		irsdk.PaceFlags | []irsdk.PaceFlags | irsdk.GenericBitField | []irsdk.GenericBitField |
+		irsdk.TrkLoc | []irsdk.TrkLoc | irsdk.TrkSurf | []irsdk.TrkSurf | irsdk.SessionState | irsdk.PitSvStatus |
+		irsdk.CarLeftRight | irsdk.PaceMode
 }
 
 // Filter the tick for only the given whitelisted fields
@@ -40,3 +48,52 @@ func GetTickValue[T TickValueType](tick Tick, key string) (T, error) {
 
 	return value, nil
 }
+
+// RawTick is a single instance of undecoded telemetry data.
+//
+// Variables are only decoded when they are requested, which avoids building a Tick for every row.
+// RawTicks returned by a Parser decode their variables in the same manner as the Parser, such as
+// reading bitfields as hex strings after Parser.UseLegacyBitFields.
+type RawTick struct {
+	buf  []byte
+	vars map[string]headers.VarHeader
+
+	// Decoding modes of the Parser the tick was read by
+	legacyBitFields bool
+	enums           bool
+}
+
+// NewRawTick wraps the given telemetry buffer and variable headers in a RawTick.
+func NewRawTick(buf []byte, vars map[string]headers.VarHeader) RawTick {
+	return RawTick{buf: buf, vars: vars}
+}
+
+// Bytes of the underlying telemetry buffer.
+func (r RawTick) Bytes() []byte { return r.buf }
+
+// Get decodes and returns the value of the given variable.
+//
+// False will be returned if the variable does not exist.
+func (r RawTick) Get(key string) (interface{}, bool) {
+	vh, ok := r.vars[key]
+	if !ok || r.buf == nil {
+		return nil, false
+	}
+
+	return readVar(r.buf, vh, r.legacyBitFields, r.enums), true
+}
+
+// Tick decodes the given whitelisted variables into a Tick.
+//
+// Variables that do not exist will be excluded.
+func (r RawTick) Tick(whitelist ...string) Tick {
+	tick := make(Tick, len(whitelist))
+
+	for _, key := range whitelist {
+		if value, ok := r.Get(key); ok {
+			tick[key] = value
+		}
+	}
+
+	return tick
+}
@@ -1,6 +1,10 @@
 package ibt
 
-import "testing"
+import (
+	"testing"
+
+	"github.com/teamjorge/ibt/headers"
+)
 
 func TestGetTickValue(t *testing.T) {
 	testTick := Tick{
@@ -34,3 +38,40 @@ func TestGetTickValue(t *testing.T) {
 		}
 	})
 }
+
+func TestRawTick(t *testing.T) {
+	vars := map[string]headers.VarHeader{
+		"Gear":  {Name: "Gear", Rtype: 2, Offset: 0, Count: 1},
+		"Speed": {Name: "Speed", Rtype: 4, Offset: 4, Count: 1},
+	}
+	raw := NewRawTick([]byte{0x5, 0x0, 0x0, 0x0, 0x0, 0x0, 0x80, 0x3f}, vars)
+
+	t.Run("test Get()", func(t *testing.T) {
+		value, ok := raw.Get("Gear")
+		if !ok || value != 5 {
+			t.Errorf("expected Gear value to be %d. received: %v", 5, value)
+		}
+
+		if _, ok := raw.Get("NotFound"); ok {
+			t.Errorf("expected key %s to not be found", "NotFound")
+		}
+	})
+
+	t.Run("test Tick()", func(t *testing.T) {
+		tick := raw.Tick("Gear", "Speed", "NotFound")
+
+		if len(tick) != 2 {
+			t.Errorf("expected tick to have %d values. received: %v", 2, tick)
+		}
+
+		if tick["Speed"] != float32(1) {
+			t.Errorf("expected Speed value to be %f. received: %v", float32(1), tick["Speed"])
+		}
+	})
+
+	t.Run("test empty RawTick", func(t *testing.T) {
+		if _, ok := (RawTick{}).Get("Gear"); ok {
+			t.Error("expected empty raw tick to not return values")
+		}
+	})
+}
@@ -0,0 +1,175 @@
+package ibt
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Trim writes the records of the stub from start up to (but excluding) end to w as a new ibt file.
+//
+// An end of 0 will write until the end of the stub. The session info of the stub is preserved, while the
+// StartTime, EndTime, LapCount, and RecordCount of the DiskHeader are recalculated from the written records.
+// The StartDate is moved forward by the number of seconds that were trimmed from the start.
+func Trim(w io.WriteSeeker, stub Stub, start, end int) error {
+	header := stub.header
+
+	if start < 0 || (end > 0 && end <= start) {
+		return fmt.Errorf("invalid record range %d to %d for stub %s", start, end, stub.Filename())
+	}
+
+	reader, closeReader, err := stub.reader()
+	if err != nil {
+		return err
+	}
+	defer closeReader()
+
+	parser := NewParser(reader, header)
+	parser.SeekRange(start, end)
+
+	sessionTime, err := parser.Float64("SessionTime")
+	if err != nil {
+		return fmt.Errorf("failed to trim stub %s: %v", stub.Filename(), err)
+	}
+
+	lap, err := parser.Int("Lap")
+	if err != nil {
+		return fmt.Errorf("failed to trim stub %s: %v", stub.Filename(), err)
+	}
+
+	writer, err := NewWriter(w, header)
+	if err != nil {
+		return fmt.Errorf("failed to create writer for stub %s: %v", stub.Filename(), err)
+	}
+
+	disk := writer.Header().DiskHeader
+	laps := make(map[int]struct{})
+
+	for {
+		raw, hasNext := parser.NextRaw()
+		if raw.buf == nil {
+			break
+		}
+
+		if writer.Records() == 0 {
+			disk.StartTime = sessionTime.Value()
+		}
+		disk.EndTime = sessionTime.Value()
+		laps[lap.Value()] = struct{}{}
+
+		if err := writer.WriteRaw(raw); err != nil {
+			return fmt.Errorf("failed to trim stub %s: %v", stub.Filename(), err)
+		}
+
+		if !hasNext {
+			break
+		}
+	}
+
+	if writer.Records() == 0 {
+		return fmt.Errorf("no records found from %d to %d for stub %s", start, end, stub.Filename())
+	}
+
+	disk.LapCount = len(laps)
+	if header.DiskHeader != nil {
+		disk.StartDate = header.DiskHeader.StartDate + int64(disk.StartTime-header.DiskHeader.StartTime)
+	}
+
+	return writer.Close()
+}
+
+// TrimLap writes the records of a single lap of the stub to w as a new ibt file.
+//
+// See SplitLaps for retrieving the laps of a stub.
+func TrimLap(w io.WriteSeeker, stub Stub, lap Lap) error { return Trim(w, stub, lap.Start, lap.End) }
+
+// TrimTime writes the records of the stub with a SessionTime from `from` up to (but excluding) `to` to w
+// as a new ibt file.
+func TrimTime(w io.WriteSeeker, stub Stub, from, to float64) error {
+	start, end, err := SessionTimeRange(stub, from, to)
+	if err != nil {
+		return err
+	}
+
+	return Trim(w, stub, start, end)
+}
+
+// SessionTimeRange finds the range of records of the stub with a SessionTime from `from` up to (but excluding) `to`.
+//
+// The returned range can be used with Trim or Parser.SeekRange.
+func SessionTimeRange(stub Stub, from, to float64) (start, end int, err error) {
+	if to <= from {
+		return 0, 0, fmt.Errorf("invalid session time range %f to %f", from, to)
+	}
+
+	frame, err := LoadChannels(stub, "SessionTime")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sessionTime, err := GetColumn[[]float64](frame, "SessionTime")
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to find session time range for stub %s: %v", stub.Filename(), err)
+	}
+
+	start = sort.SearchFloat64s(sessionTime, from)
+	end = sort.SearchFloat64s(sessionTime, to)
+
+	if start >= end {
+		return 0, 0, fmt.Errorf("no records found from %f to %f for stub %s", from, to, stub.Filename())
+	}
+
+	return start, end, nil
+}
+
+// Split writes every lap of the stub to a new ibt file in the given directory.
+//
+// Files are named after the stub and the lap number, for example "file_lap9.ibt". Repeated lap numbers
+// are suffixed with their occurrence, for example "file_lap9_2.ibt". The paths of the written
+// files are returned in the order of the laps.
+func Split(stub Stub, dir string) ([]string, error) {
+	laps, err := SplitLaps(stub)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimSuffix(filepath.Base(stub.Filename()), filepath.Ext(stub.Filename()))
+
+	files := make([]string, 0, len(laps))
+	seen := make(map[int]int)
+	for _, lap := range laps {
+		name := fmt.Sprintf("%s_lap%d.ibt", base, lap.Number)
+		// Laps with a number that was already written, such as after a reset to the pits, are numbered by occurrence
+		if n := seen[lap.Number]; n > 0 {
+			name = fmt.Sprintf("%s_lap%d_%d.ibt", base, lap.Number, n+1)
+		}
+		seen[lap.Number]++
+
+		path := filepath.Join(dir, name)
+		if err := writeLap(path, stub, lap); err != nil {
+			return files, err
+		}
+
+		files = append(files, path)
+	}
+
+	return files, nil
+}
+
+// writeLap creates the file at path and writes the lap of the stub to it.
+func writeLap(path string, stub Stub, lap Lap) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create file %s: %v", path, err)
+	}
+
+	if err := TrimLap(f, stub, lap); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
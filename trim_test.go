@@ -0,0 +1,279 @@
+package ibt
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// writeLapsTestFile writes the testing file with the records split over laps 9, 10, and 11 and returns it's stub.
+func writeLapsTestFile(t *testing.T) Stub {
+	path := writeTestFile(t, func(w *Writer, parser *Parser) error {
+		for idx := 0; ; idx++ {
+			tick, hasNext := parser.Next()
+			if tick == nil {
+				return nil
+			}
+			tick["Lap"] = 9 + idx/130
+			if err := w.WriteTick(tick); err != nil {
+				return err
+			}
+			if !hasNext {
+				return nil
+			}
+		}
+	})
+
+	stubs, err := ParseLazyStubs(path)
+	if err != nil {
+		t.Fatalf("failed to parse stubs for written file - %v", err)
+	}
+
+	return stubs[0]
+}
+
+// trimTestFile trims the stub with the trim function and returns the stub of the trimmed file.
+func trimTestFile(t *testing.T, trim func(f *os.File) error) (Stub, error) {
+	path := filepath.Join(t.TempDir(), "trimmed.ibt")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create file %s - %v", path, err)
+	}
+	defer f.Close()
+
+	if err := trim(f); err != nil {
+		return Stub{}, err
+	}
+
+	stubs, err := ParseLazyStubs(path)
+	if err != nil {
+		t.Fatalf("failed to parse stubs for trimmed file - %v", err)
+	}
+
+	return stubs[0], nil
+}
+
+func TestTrim(t *testing.T) {
+	stubs, err := ParseLazyStubs(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to parse stubs for testing file - %v", err)
+	}
+	original := stubs[0]
+
+	frame, err := LoadChannels(original, "SessionTime", "Speed")
+	if err != nil {
+		t.Fatalf("failed to load channels for testing file - %v", err)
+	}
+	sessionTime, _ := GetColumn[[]float64](frame, "SessionTime")
+	speed, _ := GetColumn[[]float32](frame, "Speed")
+
+	t.Run("test Trim tick range", func(t *testing.T) {
+		stub, err := trimTestFile(t, func(f *os.File) error { return Trim(f, original, 100, 200) })
+		if err != nil {
+			t.Errorf("expected Trim() to run without err. received error: %v", err)
+			return
+		}
+
+		disk := stub.Headers().DiskHeader
+		if disk.RecordCount != 100 || disk.LapCount != 1 {
+			t.Errorf("expected %d records and %d laps. received %d records and %d laps", 100, 1, disk.RecordCount, disk.LapCount)
+		}
+
+		if disk.StartTime != sessionTime[100] || disk.EndTime != sessionTime[199] {
+			t.Errorf("expected time range %f to %f. received %f to %f", sessionTime[100], sessionTime[199], disk.StartTime, disk.EndTime)
+		}
+
+		expectedDate := original.Headers().DiskHeader.StartDate + 1
+		if disk.StartDate != expectedDate {
+			t.Errorf("expected start date %d. received %d", expectedDate, disk.StartDate)
+		}
+
+		if !reflect.DeepEqual(stub.Headers().SessionInfo, original.Headers().SessionInfo) {
+			t.Error("expected session info to be equal to the original")
+		}
+
+		trimmed, err := LoadChannels(stub, "Speed")
+		if err != nil {
+			t.Errorf("expected trimmed channels to load without err. received error: %v", err)
+			return
+		}
+
+		trimmedSpeed, _ := GetColumn[[]float32](trimmed, "Speed")
+		if !reflect.DeepEqual(trimmedSpeed, speed[100:200]) {
+			t.Error("expected trimmed records to be equal to the original range")
+		}
+	})
+
+	t.Run("test Trim until end", func(t *testing.T) {
+		stub, err := trimTestFile(t, func(f *os.File) error { return Trim(f, original, 380, 0) })
+		if err != nil {
+			t.Errorf("expected Trim() to run without err. received error: %v", err)
+			return
+		}
+
+		disk := stub.Headers().DiskHeader
+		if disk.RecordCount != 10 || disk.EndTime != sessionTime[389] {
+			t.Errorf("expected %d records ending at %f. received %d records ending at %f", 10, sessionTime[389], disk.RecordCount, disk.EndTime)
+		}
+	})
+
+	t.Run("test Trim invalid range", func(t *testing.T) {
+		for _, r := range [][2]int{{-1, 10}, {10, 5}, {500, 0}} {
+			if _, err := trimTestFile(t, func(f *os.File) error { return Trim(f, original, r[0], r[1]) }); err == nil {
+				t.Errorf("expected Trim() to return an error for range %d to %d", r[0], r[1])
+			}
+		}
+	})
+
+	t.Run("test TrimTime", func(t *testing.T) {
+		stub, err := trimTestFile(t, func(f *os.File) error {
+			return TrimTime(f, original, sessionTime[50], sessionTime[110])
+		})
+		if err != nil {
+			t.Errorf("expected TrimTime() to run without err. received error: %v", err)
+			return
+		}
+
+		disk := stub.Headers().DiskHeader
+		if disk.RecordCount != 60 || disk.StartTime != sessionTime[50] {
+			t.Errorf("expected %d records starting at %f. received %d records starting at %f", 60, sessionTime[50], disk.RecordCount, disk.StartTime)
+		}
+	})
+
+	t.Run("test SessionTimeRange", func(t *testing.T) {
+		start, end, err := SessionTimeRange(original, 0, sessionTime[10]+0.001)
+		if err != nil || start != 0 || end != 11 {
+			t.Errorf("expected range %d to %d. received %d to %d with error: %v", 0, 11, start, end, err)
+		}
+
+		if _, _, err := SessionTimeRange(original, 2000, 3000); err == nil {
+			t.Error("expected SessionTimeRange() to return an error for a range without records")
+		}
+
+		if _, _, err := SessionTimeRange(original, 10, 5); err == nil {
+			t.Error("expected SessionTimeRange() to return an error for an invalid range")
+		}
+	})
+}
+
+func TestSplit(t *testing.T) {
+	stub := writeLapsTestFile(t)
+
+	t.Run("test TrimLap", func(t *testing.T) {
+		laps, err := SplitLaps(stub)
+		if err != nil || len(laps) != 3 {
+			t.Fatalf("expected %d laps. received %d with error: %v", 3, len(laps), err)
+		}
+
+		trimmed, err := trimTestFile(t, func(f *os.File) error { return TrimLap(f, stub, laps[1]) })
+		if err != nil {
+			t.Errorf("expected TrimLap() to run without err. received error: %v", err)
+			return
+		}
+
+		disk := trimmed.Headers().DiskHeader
+		if disk.RecordCount != 130 || disk.LapCount != 1 {
+			t.Errorf("expected %d records and %d laps. received %d records and %d laps", 130, 1, disk.RecordCount, disk.LapCount)
+		}
+
+		proc := &testProcessor{whitelist: []string{"Lap"}}
+		if err := Process(context.Background(), StubGroup{trimmed}, proc); err != nil {
+			t.Errorf("expected Process() to run without err. received error: %v", err)
+			return
+		}
+
+		if len(proc.results) != laps[1].Len() {
+			t.Errorf("expected %d ticks to be processed. received %d", laps[1].Len(), len(proc.results))
+			return
+		}
+
+		for idx, tick := range proc.results {
+			if tick["Lap"] != 10 {
+				t.Errorf("expected tick %d to be of lap %d. received %v", idx, 10, tick["Lap"])
+				return
+			}
+		}
+	})
+
+	t.Run("test Split", func(t *testing.T) {
+		dir := t.TempDir()
+		files, err := Split(stub, dir)
+		if err != nil {
+			t.Errorf("expected Split() to run without err. received error: %v", err)
+			return
+		}
+
+		expected := []string{
+			filepath.Join(dir, "written_lap9.ibt"),
+			filepath.Join(dir, "written_lap10.ibt"),
+			filepath.Join(dir, "written_lap11.ibt"),
+		}
+		if !reflect.DeepEqual(files, expected) {
+			t.Errorf("expected files %v. received %v", expected, files)
+			return
+		}
+
+		for idx, file := range files {
+			stubs, err := ParseLazyStubs(file)
+			if err != nil {
+				t.Errorf("expected %s to be parsed without err. received error: %v", file, err)
+				continue
+			}
+
+			frame, err := LoadChannels(stubs[0], "Lap")
+			if err != nil {
+				t.Errorf("expected channels of %s to load without err. received error: %v", file, err)
+				continue
+			}
+
+			laps, _ := GetColumn[[]int](frame, "Lap")
+			if frame.Len != 130 || laps[0] != 9+idx || laps[129] != 9+idx {
+				t.Errorf("expected %d records of lap %d in %s. received %d", 130, 9+idx, file, frame.Len)
+			}
+		}
+	})
+
+	t.Run("test Split repeated laps", func(t *testing.T) {
+		path := writeTestFile(t, func(w *Writer, parser *Parser) error {
+			for idx := 0; ; idx++ {
+				tick, hasNext := parser.Next()
+				if tick == nil {
+					return nil
+				}
+				tick["Lap"] = 9
+				if idx >= 200 && idx < 300 {
+					tick["Lap"] = 10
+				}
+				if err := w.WriteTick(tick); err != nil {
+					return err
+				}
+				if !hasNext {
+					return nil
+				}
+			}
+		})
+
+		stubs, err := ParseLazyStubs(path)
+		if err != nil {
+			t.Fatalf("failed to parse stubs for written file - %v", err)
+		}
+
+		dir := t.TempDir()
+		files, err := Split(stubs[0], dir)
+		if err != nil {
+			t.Errorf("expected Split() to run without err. received error: %v", err)
+			return
+		}
+
+		expected := []string{
+			filepath.Join(dir, "written_lap9.ibt"),
+			filepath.Join(dir, "written_lap10.ibt"),
+			filepath.Join(dir, "written_lap9_2.ibt"),
+		}
+		if !reflect.DeepEqual(files, expected) {
+			t.Errorf("expected files %v. received %v", expected, files)
+		}
+	})
+}
@@ -19,6 +19,11 @@ func Byte4ToInt(in []byte) int {
 	return converted
 }
 
+// Byte4ToUint32 will convert the little endian 4 byte value into an uint32
+func Byte4ToUint32(in []byte) uint32 {
+	return binary.LittleEndian.Uint32(in)
+}
+
 // Byte4ToFloat will convert the little endian 4 byte value into an float
 func Byte4ToFloat(in []byte) float32 {
 	bits := binary.LittleEndian.Uint32(in)
@@ -46,3 +51,45 @@ func Byte8ToInt64(in []byte) int64 {
 	bits := binary.LittleEndian.Uint64(in)
 	return int64(bits)
 }
+
+// IntToByte4 will convert the int into a little endian 4 byte value
+func IntToByte4(in int) []byte {
+	out := make([]byte, 4)
+	binary.LittleEndian.PutUint32(out, uint32(in))
+	return out
+}
+
+// Uint32ToByte4 will convert the uint32 into a little endian 4 byte value
+func Uint32ToByte4(in uint32) []byte {
+	out := make([]byte, 4)
+	binary.LittleEndian.PutUint32(out, in)
+	return out
+}
+
+// FloatToByte4 will convert the float into a little endian 4 byte value
+func FloatToByte4(in float32) []byte {
+	return Uint32ToByte4(math.Float32bits(in))
+}
+
+// FloatToByte8 will convert the float into a little endian 8 byte value
+func FloatToByte8(in float64) []byte {
+	out := make([]byte, 8)
+	binary.LittleEndian.PutUint64(out, math.Float64bits(in))
+	return out
+}
+
+// Int64ToByte8 will convert the int64 into a little endian 8 byte value
+func Int64ToByte8(in int64) []byte {
+	out := make([]byte, 8)
+	binary.LittleEndian.PutUint64(out, uint64(in))
+	return out
+}
+
+// StringToBytes will convert the string to a value of the given size, padded with zero bytes.
+//
+// Strings longer than the size are truncated.
+func StringToBytes(in string, size int) []byte {
+	out := make([]byte, size)
+	copy(out, in)
+	return out
+}
@@ -1,6 +1,7 @@
 package utilities
 
 import (
+	"bytes"
 	"testing"
 )
 
@@ -21,6 +22,14 @@ func TestEndian(t *testing.T) {
 		}
 	})
 
+	t.Run("test Byte4ToUint32 valid", func(t *testing.T) {
+		res := Byte4ToUint32([]byte{0xff, 0xff, 0xff, 0xff})
+
+		if res != 4294967295 {
+			t.Errorf("expected result to be %d, got %d", uint32(4294967295), res)
+		}
+	})
+
 	t.Run("test Byte4ToFloat", func(t *testing.T) {
 		res := Byte4ToFloat([]byte{0x1, 0x7c, 0x17, 0xba})
 
@@ -68,4 +77,47 @@ func TestEndian(t *testing.T) {
 			t.Errorf("expected result to be %d, got %d", 17409, res)
 		}
 	})
+
+	t.Run("test IntToByte4 round trip", func(t *testing.T) {
+		for _, value := range []int{0, 17409, -1} {
+			if res := Byte4ToInt(IntToByte4(value)); res != value {
+				t.Errorf("expected result to be %d, got %d", value, res)
+			}
+		}
+	})
+
+	t.Run("test Uint32ToByte4 round trip", func(t *testing.T) {
+		if res := Byte4ToUint32(Uint32ToByte4(0x10040200)); res != 0x10040200 {
+			t.Errorf("expected result to be %d, got %d", 0x10040200, res)
+		}
+	})
+
+	t.Run("test FloatToByte4 round trip", func(t *testing.T) {
+		if res := Byte4ToFloat(FloatToByte4(37.678566)); res != 37.678566 {
+			t.Errorf("expected result to be %f, got %f", 37.678566, res)
+		}
+	})
+
+	t.Run("test FloatToByte8 round trip", func(t *testing.T) {
+		if res := Byte8ToFloat(FloatToByte8(932.000000635264)); res != 932.000000635264 {
+			t.Errorf("expected result to be %f, got %f", 932.000000635264, res)
+		}
+	})
+
+	t.Run("test Int64ToByte8 round trip", func(t *testing.T) {
+		if res := Byte8ToInt64(Int64ToByte8(1719258336)); res != 1719258336 {
+			t.Errorf("expected result to be %d, got %d", 1719258336, res)
+		}
+	})
+
+	t.Run("test StringToBytes", func(t *testing.T) {
+		res := StringToBytes("test", 6)
+		if !bytes.Equal(res, []byte{'t', 'e', 's', 't', 0x0, 0x0}) {
+			t.Errorf("expected result to be padded. got %v", res)
+		}
+
+		if res := StringToBytes("testring", 4); string(res) != "test" {
+			t.Errorf("expected result to be truncated to %s, got %s", "test", res)
+		}
+	})
 }
@@ -2,12 +2,14 @@ package ibt
 
 import (
 	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
 	"github.com/teamjorge/ibt/utilities"
 )
 
 // readVarValue extracts the telemetry variable value from the given buffer based on the provided metadata.
 //
-// This function will ensure that the underlying type of the value is correct.
+// This function will ensure that the underlying type of the value is correct. Bitfields are returned as their
+// typed irsdk bitfield, such as irsdk.SessionFlags, based on the name of the variable.
 func readVarValue(buf []byte, vh headers.VarHeader) interface{} {
 	var rbuf []byte
 
@@ -39,11 +41,11 @@ func readVarValue(buf []byte, vh headers.VarHeader) interface{} {
 			value = res
 		case 3:
 			rbuf = buf[offset : offset+vh.Count*4]
-			res := make([]string, 0)
+			res := make([]uint32, 0, vh.Count)
 			for i := 0; i < len(rbuf); i += 4 {
-				res = append(res, utilities.Byte4toBitField(rbuf[i:i+4]))
+				res = append(res, utilities.Byte4ToUint32(rbuf[i:i+4]))
 			}
-			value = res
+			value = irsdk.DecodeBitFields(vh.Name, res)
 		case 4:
 			rbuf = buf[offset : offset+vh.Count*4]
 			res := make([]float32, 0)
@@ -72,7 +74,7 @@ func readVarValue(buf []byte, vh headers.VarHeader) interface{} {
 			value = utilities.Byte4ToInt(rbuf)
 		case 3:
 			rbuf = buf[offset : offset+4]
-			value = utilities.Byte4toBitField(rbuf)
+			value = irsdk.DecodeBitField(vh.Name, utilities.Byte4ToUint32(rbuf))
 		case 4:
 			rbuf = buf[offset : offset+4]
 			value = utilities.Byte4ToFloat(rbuf)
@@ -84,3 +86,52 @@ func readVarValue(buf []byte, vh headers.VarHeader) interface{} {
 
 	return value
 }
+
+// readVar extracts the telemetry variable value from the given buffer, reading bitfields as hex strings
+// when legacyBitFields is set and known enum variables as typed irsdk enums when enums is set.
+func readVar(buf []byte, vh headers.VarHeader, legacyBitFields, enums bool) interface{} {
+	if legacyBitFields && vh.Rtype == 3 {
+		return readLegacyBitField(buf, vh)
+	}
+
+	value := readVarValue(buf, vh)
+	if enums && vh.Rtype == 2 {
+		value = decodeEnumValue(value, vh)
+	}
+
+	return value
+}
+
+// readLegacyBitField reads a bitfield variable as a hex string, such as "0x10040200".
+//
+// Arrays of bitfields are returned as a []string.
+func readLegacyBitField(buf []byte, vh headers.VarHeader) interface{} {
+	if vh.Count > 1 {
+		res := make([]string, 0, vh.Count)
+		for i := 0; i < vh.Count; i++ {
+			start := vh.Offset + i*4
+			res = append(res, utilities.Byte4toBitField(buf[start:start+4]))
+		}
+		return res
+	}
+
+	return utilities.Byte4toBitField(buf[vh.Offset : vh.Offset+4])
+}
+
+// decodeEnumValue converts an int or []int value to the typed irsdk enum of the variable, such as irsdk.TrkLoc.
+//
+// The value is returned unchanged when the variable is not a known enum.
+func decodeEnumValue(value interface{}, vh headers.VarHeader) interface{} {
+	switch v := value.(type) {
+	case int:
+		if enum, ok := irsdk.DecodeEnum(vh.Name, v); ok {
+			return enum
+		}
+	case []int:
+		if enums, ok := irsdk.DecodeEnums(vh.Name, v); ok {
+			return enums
+		}
+	}
+
+	return value
+}
@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
 )
 
 func TestValue(t *testing.T) {
@@ -119,22 +120,56 @@ func TestValue(t *testing.T) {
 
 		value := readVarValue(bitValue, varHeader)
 
-		if reflect.TypeOf(value).String() != "string" {
-			t.Errorf("expected return value to be string. got %v of type %T", value, value)
+		if reflect.TypeOf(value).String() != "irsdk.GenericBitField" {
+			t.Errorf("expected return value to be irsdk.GenericBitField. got %v of type %T", value, value)
 		}
 
-		if value.(string) != "0x10040200" {
-			t.Errorf("expected returned value to be %v. received %v", "0x10040200", value.(string))
+		if value.(irsdk.BitField).Raw() != 0x10040200 {
+			t.Errorf("expected returned value to be %#x. received %v", 0x10040200, value)
+		}
+
+		varHeader.Name = "SessionFlags"
+
+		flags := readVarValue(bitValue, varHeader)
+
+		if !flags.(irsdk.SessionFlags).Has(irsdk.OneLapToGreen | irsdk.Servicible | irsdk.StartHidden) {
+			t.Errorf("expected returned flags to be %s. received %v", "OneLapToGreen|Servicible|StartHidden", flags)
 		}
 
 		varHeader.Count = 2
+		varHeader.Name = "CarIdxPaceFlags"
 
 		values := readVarValue(bitArray, varHeader)
 
-		if reflect.TypeOf(values).String() != "[]string" {
-			t.Errorf("expected return value to be []string. got %v of type %T", values, values)
+		if reflect.TypeOf(values).String() != "[]irsdk.PaceFlags" {
+			t.Errorf("expected return value to be []irsdk.PaceFlags. got %v of type %T", values, values)
+		}
+
+		if values.([]irsdk.PaceFlags)[0] != 0x10040200 || values.([]irsdk.PaceFlags)[1] != 0x14080400 {
+			t.Errorf("expected returned values to be %v. received %v", []uint32{0x10040200, 0x14080400}, values)
+		}
+	})
+
+	t.Run("test readLegacyBitField", func(t *testing.T) {
+		bitValue := []byte{0x0, 0x2, 0x4, 0x10}
+		bitArray := []byte{0x0, 0x2, 0x4, 0x10, 0x0, 0x4, 0x8, 0x14}
+
+		varHeader := headers.VarHeader{
+			Count:  1,
+			Rtype:  3,
+			Offset: 0,
+		}
+
+		value := readLegacyBitField(bitValue, varHeader)
+
+		if value.(string) != "0x10040200" {
+			t.Errorf("expected returned value to be %v. received %v", "0x10040200", value)
 		}
 
+		varHeader.Count = 2
+
+		values := readLegacyBitField(bitArray, varHeader)
+
 		if values.([]string)[0] != "0x10040200" || values.([]string)[1] != "0x14080400" {
 			t.Errorf("expected returned values to be %v. received %v", []string{"0x10040200", "0x14080400"}, values)
 		}
@@ -213,4 +248,25 @@ func TestValue(t *testing.T) {
 	// five := []byte{0x0, 0x0, 0x0, 0x0, 0x0, 0x75, 0x22, 0x41}
 
 	// fourArr := []byte{0xc0, 0x42, 0x56, 0xc1, 0x8e, 0x7d, 0x30, 0xc2}
+
+	t.Run("test decodeEnumValue", func(t *testing.T) {
+		varHeader := headers.VarHeader{Name: "CarIdxTrackSurface", Rtype: 2, Count: 2}
+
+		values := decodeEnumValue([]int{1, 3}, varHeader)
+		if !reflect.DeepEqual(values, []irsdk.TrkLoc{irsdk.TrkLocInPitStall, irsdk.TrkLocOnTrack}) {
+			t.Errorf("expected values to be []irsdk.TrkLoc. received %v of type %T", values, values)
+		}
+
+		varHeader = headers.VarHeader{Name: "SessionState", Rtype: 2, Count: 1}
+
+		if value := decodeEnumValue(4, varHeader); value != irsdk.SessionStateRacing {
+			t.Errorf("expected value to be %v. received %v of type %T", irsdk.SessionStateRacing, value, value)
+		}
+
+		varHeader = headers.VarHeader{Name: "Gear", Rtype: 2, Count: 1}
+
+		if value := decodeEnumValue(4, varHeader); value != 4 {
+			t.Errorf("expected value to remain an int. received %v of type %T", value, value)
+		}
+	})
 }
@@ -0,0 +1,323 @@
+package ibt
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
+	"github.com/teamjorge/ibt/utilities"
+)
+
+// Writer writes telemetry ticks to a new ibt file.
+//
+// The headers are written when the Writer is created, followed by a telemetry buffer for every tick that is written.
+// Close must be called once all ticks are written to update the DiskHeader with the number of records.
+//
+// The file is laid out in the same manner as the files written by iRacing: the TelemetryHeader, DiskHeader,
+// VarHeaders, SessionInfo YAML, and lastly the telemetry buffers. Every written tick is a record of the file,
+// meaning a Parser will return the same number of ticks as were written.
+type Writer struct {
+	w      io.WriteSeeker
+	header *headers.Header
+
+	// Reusable buffer for encoding ticks
+	buf     []byte
+	records int
+	closed  bool
+}
+
+// NewWriter creates a Writer and writes the given headers to w.
+//
+// The VarHeaders and BufLen of the header determine the layout of every tick and are written as is, allowing
+// RawTicks of a Parser with the same header to be copied directly. The offsets of the SessionInfo and the
+// telemetry buffers are calculated by the Writer. A header without a DiskHeader, such as one of a live
+// telemetry buffer, will have one created. When the header has a RawSessionInfo, the SessionInfo is merged
+// over it to preserve the fields that are not part of headers.Session (see headers.EncodeMergedSessionInfo).
+func NewWriter(w io.WriteSeeker, header *headers.Header) (*Writer, error) {
+	if header == nil || header.TelemetryHeader == nil || header.SessionInfo == nil {
+		return nil, errors.New("a header with a telemetry header and session info is required")
+	}
+
+	if err := validateVarHeaders(header.VarHeader, header.TelemetryHeader.BufLen); err != nil {
+		return nil, err
+	}
+
+	telemetryHeader := *header.TelemetryHeader
+
+	diskHeader := headers.DiskHeader{}
+	if header.DiskHeader != nil {
+		diskHeader = *header.DiskHeader
+	}
+
+	sessionInfo, err := headers.EncodeMergedSessionInfo(header.SessionInfo, header.RawSessionInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode session info: %v", err)
+	}
+
+	rawSessionInfo, err := headers.DecodeSessionInfo(sessionInfo)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode session info: %v", err)
+	}
+
+	varHeaders := headers.EncodeVarHeaders(header.VarHeader)
+
+	telemetryHeader.Version = 2
+	telemetryHeader.Status = 1
+	telemetryHeader.NumVars = len(header.VarHeader)
+	telemetryHeader.VarHeaderOffset = headers.TELEMETRY_HEADER_BYTES_SIZE + headers.DISK_HEADER_BYTES_SIZE
+	telemetryHeader.SessionInfoOffset = telemetryHeader.VarHeaderOffset + len(varHeaders)
+	telemetryHeader.SessionInfoLength = len(sessionInfo)
+	telemetryHeader.NumBuf = 1
+	telemetryHeader.BufOffset = telemetryHeader.SessionInfoOffset + telemetryHeader.SessionInfoLength
+
+	varBuffer := headers.VarBuffer{BufOffset: telemetryHeader.BufOffset}
+	if len(header.VarBuffers) > 0 {
+		varBuffer.TickCount = header.VarBuffers[0].TickCount
+	}
+
+	writer := &Writer{
+		w: w,
+		header: &headers.Header{
+			TelemetryHeader: &telemetryHeader,
+			DiskHeader:      &diskHeader,
+			VarHeader:       header.VarHeader,
+			SessionInfo:     header.SessionInfo,
+			RawSessionInfo:  rawSessionInfo,
+			VarBuffers:      []headers.VarBuffer{varBuffer},
+		},
+		buf: make([]byte, telemetryHeader.BufLen),
+	}
+
+	if _, err := w.Seek(0, io.SeekStart); err != nil {
+		return nil, fmt.Errorf("failed to seek to start of file: %v", err)
+	}
+
+	if err := writer.writeFileHeaders(); err != nil {
+		return nil, err
+	}
+
+	for _, b := range [][]byte{varHeaders, sessionInfo} {
+		if _, err := w.Write(b); err != nil {
+			return nil, fmt.Errorf("failed to write headers: %v", err)
+		}
+	}
+
+	return writer, nil
+}
+
+// Header that is being written.
+//
+// The DiskHeader can be updated before calling Close, for example to set the StartTime and EndTime of the
+// written ticks. The RecordCount is always set to the number of written ticks.
+func (w *Writer) Header() *headers.Header { return w.header }
+
+// Records returns the number of ticks that have been written.
+func (w *Writer) Records() int { return w.records }
+
+// WriteRaw writes the buffer of the raw tick as the next record.
+//
+// The raw tick should originate from a Parser with the same VarHeaders as the Writer.
+func (w *Writer) WriteRaw(raw RawTick) error {
+	if len(raw.buf) != len(w.buf) {
+		return fmt.Errorf("expected a raw tick of %d bytes. received %d bytes", len(w.buf), len(raw.buf))
+	}
+
+	return w.writeRecord(raw.buf)
+}
+
+// WriteTick encodes the values of the tick and writes it as the next record.
+//
+// Variables that are missing from the tick are written as zero. Values can be of any of the types returned
+// by a Parser, including typed irsdk bitfields and enums, and legacy hex string bitfields. Values of variables
+// that are not part of the header are ignored.
+func (w *Writer) WriteTick(tick Tick) error {
+	clear(w.buf)
+
+	for name, value := range tick {
+		vh, ok := w.header.VarHeader[name]
+		if !ok || value == nil {
+			continue
+		}
+
+		if err := encodeVarValue(w.buf, vh, value); err != nil {
+			return fmt.Errorf("failed to encode variable %s: %v", name, err)
+		}
+	}
+
+	return w.writeRecord(w.buf)
+}
+
+func (w *Writer) writeRecord(buf []byte) error {
+	if w.closed {
+		return errors.New("writer is closed")
+	}
+
+	if _, err := w.w.Write(buf); err != nil {
+		return fmt.Errorf("failed to write record %d: %v", w.records, err)
+	}
+
+	w.records++
+
+	return nil
+}
+
+// Close updates the headers with the number of written ticks.
+//
+// The underlying writer is not closed.
+func (w *Writer) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	w.header.DiskHeader.RecordCount = w.records
+	if w.header.VarBuffers[0].TickCount == 0 {
+		w.header.VarBuffers[0].TickCount = w.records
+	}
+
+	if _, err := w.w.Seek(0, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek to start of file: %v", err)
+	}
+
+	if err := w.writeFileHeaders(); err != nil {
+		return err
+	}
+
+	if _, err := w.w.Seek(0, io.SeekEnd); err != nil {
+		return fmt.Errorf("failed to seek to end of file: %v", err)
+	}
+
+	return nil
+}
+
+// writeFileHeaders writes the TelemetryHeader and DiskHeader at the current position.
+func (w *Writer) writeFileHeaders() error {
+	telemetryHeader, err := headers.EncodeTelemetryHeader(w.header.TelemetryHeader, w.header.VarBuffers)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry header: %v", err)
+	}
+
+	if _, err := w.w.Write(telemetryHeader); err != nil {
+		return fmt.Errorf("failed to write telemetry header: %v", err)
+	}
+
+	if _, err := w.w.Write(headers.EncodeDiskHeader(w.header.DiskHeader)); err != nil {
+		return fmt.Errorf("failed to write disk header: %v", err)
+	}
+
+	return nil
+}
+
+// varValueSize is the number of bytes of a single value of each Rtype
+var varValueSize = map[int]int{0: 1, 1: 1, 2: 4, 3: 4, 4: 4, 5: 8}
+
+// validateVarHeaders ensures the values of all variables fit within a telemetry buffer of the given length.
+func validateVarHeaders(vars map[string]headers.VarHeader, bufLen int) error {
+	for name, vh := range vars {
+		size, ok := varValueSize[vh.Rtype]
+		if !ok {
+			return fmt.Errorf("unknown type %d of variable %s", vh.Rtype, name)
+		}
+
+		count := vh.Count
+		if count < 1 {
+			count = 1
+		}
+
+		if vh.Offset < 0 || vh.Offset+count*size > bufLen {
+			return fmt.Errorf("variable %s at offset %d does not fit in the buffer length %d", name, vh.Offset, bufLen)
+		}
+	}
+
+	return nil
+}
+
+// encodeVarValue writes the value of the variable to the buffer at the offset of the variable.
+func encodeVarValue(buf []byte, vh headers.VarHeader, value interface{}) error {
+	size, ok := varValueSize[vh.Rtype]
+	if !ok {
+		return fmt.Errorf("unknown variable type %d", vh.Rtype)
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice {
+		return encodeVarScalar(buf[vh.Offset:vh.Offset+size], vh.Rtype, value)
+	}
+
+	if rv.Len() > vh.Count {
+		return fmt.Errorf("expected a maximum of %d values. received %d", vh.Count, rv.Len())
+	}
+
+	for i := 0; i < rv.Len(); i++ {
+		start := vh.Offset + i*size
+		if err := encodeVarScalar(buf[start:start+size], vh.Rtype, rv.Index(i).Interface()); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// encodeVarScalar writes a single value of the given Rtype to the buffer.
+func encodeVarScalar(buf []byte, rtype int, value interface{}) error {
+	switch v := value.(type) {
+	case irsdk.BitField:
+		value = v.Raw()
+	case irsdk.Enum:
+		value = v.Raw()
+	case string:
+		raw, err := strconv.ParseUint(strings.TrimPrefix(v, "0x"), 16, 32)
+		if err != nil {
+			return fmt.Errorf("invalid bitfield %s: %v", v, err)
+		}
+		value = uint32(raw)
+	}
+
+	switch rtype {
+	case 0:
+		v, ok := value.(uint8)
+		if !ok {
+			return fmt.Errorf("expected a uint8 value. received %T", value)
+		}
+		buf[0] = v
+	case 1:
+		v, ok := value.(bool)
+		if !ok {
+			return fmt.Errorf("expected a bool value. received %T", value)
+		}
+		if v {
+			buf[0] = 1
+		}
+	case 2:
+		v, ok := value.(int)
+		if !ok {
+			return fmt.Errorf("expected an int value. received %T", value)
+		}
+		copy(buf, utilities.IntToByte4(v))
+	case 3:
+		v, ok := value.(uint32)
+		if !ok {
+			return fmt.Errorf("expected a bitfield value. received %T", value)
+		}
+		copy(buf, utilities.Uint32ToByte4(v))
+	case 4:
+		v, ok := value.(float32)
+		if !ok {
+			return fmt.Errorf("expected a float32 value. received %T", value)
+		}
+		copy(buf, utilities.FloatToByte4(v))
+	case 5:
+		v, ok := value.(float64)
+		if !ok {
+			return fmt.Errorf("expected a float64 value. received %T", value)
+		}
+		copy(buf, utilities.FloatToByte8(v))
+	}
+
+	return nil
+}
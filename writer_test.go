@@ -0,0 +1,213 @@
+package ibt
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/teamjorge/ibt/headers"
+	"github.com/teamjorge/ibt/irsdk"
+)
+
+// writeTestFile writes all records of the testing file with the write function and returns the path of the new file.
+func writeTestFile(t *testing.T, write func(w *Writer, parser *Parser) error) string {
+	stubs, err := ParseStubs(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to parse stubs for testing file - %v", err)
+	}
+	defer stubs.Close()
+
+	path := filepath.Join(t.TempDir(), "written.ibt")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("failed to create file %s - %v", path, err)
+	}
+	defer f.Close()
+
+	w, err := NewWriter(f, stubs[0].Headers())
+	if err != nil {
+		t.Fatalf("expected NewWriter() to run without err. received error: %v", err)
+	}
+
+	parser := NewParser(stubs[0].r, stubs[0].Headers(), headers.AvailableVars(stubs[0].Headers().VarHeader)...)
+
+	if err := write(w, parser); err != nil {
+		t.Fatalf("expected ticks to be written without err. received error: %v", err)
+	}
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("expected Close() to run without err. received error: %v", err)
+	}
+
+	return path
+}
+
+func TestWriter(t *testing.T) {
+	original, err := ParseStubs(".testing/valid_test_file.ibt")
+	if err != nil {
+		t.Fatalf("failed to parse stubs for testing file - %v", err)
+	}
+	defer original.Close()
+
+	originalHeader := original[0].Headers()
+
+	t.Run("test Writer raw round trip", func(t *testing.T) {
+		path := writeTestFile(t, func(w *Writer, parser *Parser) error {
+			for {
+				raw, hasNext := parser.NextRaw()
+				if raw.buf == nil {
+					return nil
+				}
+				if err := w.WriteRaw(raw); err != nil {
+					return err
+				}
+				if !hasNext {
+					return nil
+				}
+			}
+		})
+
+		stubs, err := ParseStubs(path)
+		if err != nil {
+			t.Errorf("expected written file to be parsed without err. received error: %v", err)
+			return
+		}
+		defer stubs.Close()
+
+		header := stubs[0].Headers()
+
+		if !reflect.DeepEqual(header.DiskHeader, originalHeader.DiskHeader) {
+			t.Errorf("expected disk header %+v. received %+v", originalHeader.DiskHeader, header.DiskHeader)
+		}
+
+		if !reflect.DeepEqual(header.VarHeader, originalHeader.VarHeader) {
+			t.Error("expected var headers to be equal to the original")
+		}
+
+		if !reflect.DeepEqual(header.SessionInfo, originalHeader.SessionInfo) {
+			t.Error("expected session info to be equal to the original")
+		}
+
+		// Fields that are not part of headers.Session are preserved
+		if value, ok := header.RawSessionInfo.Lookup("DriverInfo.Drivers[0].FaceType"); !ok || value != 4 {
+			t.Errorf("expected raw session info to be preserved. received face type %v", value)
+		}
+
+		if header.TelemetryHeader.TickRate != 60 || header.TelemetryHeader.BufLen != originalHeader.TelemetryHeader.BufLen ||
+			header.VarBuffers[0].TickCount != originalHeader.VarBuffers[0].TickCount {
+			t.Errorf("expected telemetry header to match the original. received %+v", header.TelemetryHeader)
+		}
+
+		originalParser := NewParser(original[0].r, originalHeader)
+		parser := NewParser(stubs[0].r, header)
+		for {
+			expected, hasNext := originalParser.NextRaw()
+			received, _ := parser.NextRaw()
+			if !bytes.Equal(expected.buf, received.buf) {
+				t.Error("expected written records to be equal to the original")
+				return
+			}
+			if !hasNext {
+				break
+			}
+		}
+	})
+
+	t.Run("test Writer tick round trip", func(t *testing.T) {
+		path := writeTestFile(t, func(w *Writer, parser *Parser) error {
+			parser.UseEnums(true)
+			for {
+				tick, hasNext := parser.Next()
+				if tick == nil {
+					return nil
+				}
+				if err := w.WriteTick(tick); err != nil {
+					return err
+				}
+				if !hasNext {
+					return nil
+				}
+			}
+		})
+
+		stubs, err := ParseStubs(path)
+		if err != nil {
+			t.Errorf("expected written file to be parsed without err. received error: %v", err)
+			return
+		}
+		defer stubs.Close()
+
+		whitelist := headers.AvailableVars(originalHeader.VarHeader)
+		originalParser := NewParser(original[0].r, originalHeader, whitelist...)
+		parser := NewParser(stubs[0].r, stubs[0].Headers(), whitelist...)
+		ticks := 0
+		for {
+			expected, hasNext := originalParser.Next()
+			received, receivedHasNext := parser.Next()
+			if !reflect.DeepEqual(expected, received) || hasNext != receivedHasNext {
+				t.Errorf("expected written tick %d to be equal to the original", ticks)
+				return
+			}
+			ticks++
+			if !hasNext {
+				break
+			}
+		}
+
+		if ticks != originalHeader.DiskHeader.RecordCount {
+			t.Errorf("expected %d ticks to be read. received %d", originalHeader.DiskHeader.RecordCount, ticks)
+		}
+	})
+
+	t.Run("test Writer invalid values", func(t *testing.T) {
+		writeTestFile(t, func(w *Writer, parser *Parser) error {
+			if err := w.WriteTick(Tick{"Speed": "fast"}); err == nil {
+				t.Error("expected WriteTick() to return an error for an invalid value")
+			}
+
+			if err := w.WriteTick(Tick{"SteeringWheelTorque_ST": make([]float32, 7)}); err == nil {
+				t.Error("expected WriteTick() to return an error for too many values")
+			}
+
+			if err := w.WriteRaw(NewRawTick(make([]byte, 10), nil)); err == nil {
+				t.Error("expected WriteRaw() to return an error for a buffer of the wrong size")
+			}
+
+			return w.WriteTick(Tick{"SessionFlags": irsdk.SessionFlags(0x10040200), "Lap": 3, "Unknown": 1})
+		})
+	})
+
+	t.Run("test NewWriter missing headers", func(t *testing.T) {
+		f, err := os.Create(filepath.Join(t.TempDir(), "invalid.ibt"))
+		if err != nil {
+			t.Fatalf("failed to create file - %v", err)
+		}
+		defer f.Close()
+
+		if _, err := NewWriter(f, &headers.Header{}); err == nil {
+			t.Error("expected NewWriter() to return an error when headers are missing")
+		}
+	})
+
+	t.Run("test NewWriter variable outside of buffer", func(t *testing.T) {
+		f, err := os.Create(filepath.Join(t.TempDir(), "invalid.ibt"))
+		if err != nil {
+			t.Fatalf("failed to create file - %v", err)
+		}
+		defer f.Close()
+
+		telemetryHeader := headers.TelemetryHeader{BufLen: 8}
+		header := &headers.Header{
+			TelemetryHeader: &telemetryHeader,
+			SessionInfo:     &headers.Session{},
+			VarHeader:       map[string]headers.VarHeader{"Torque": {Name: "Torque", Rtype: 4, Offset: 4, Count: 6}},
+		}
+
+		if _, err := NewWriter(f, header); err == nil || !strings.Contains(err.Error(), "Torque") {
+			t.Errorf("expected NewWriter() to return an error for a variable outside of the buffer. received %v", err)
+		}
+	})
+}